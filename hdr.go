@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	hdrhistogram "github.com/HdrHistogram/hdrhistogram-go"
+)
+
+// hdrShardCount bounds lock contention on the merged latency histogram:
+// each request picks a shard round-robin instead of every goroutine
+// fighting over one mutex, the same tradeoff latencyHistogram's
+// cumulative buckets make for the Prometheus endpoint.
+const hdrShardCount = 16
+
+// hdrLowestTrackableMicros/hdrHighestTrackableMicros/hdrSignificantFigures
+// bound every latencyHDR to 1us-60s at 3 significant digits, the range
+// HdrHistogram itself recommends for request latency.
+const (
+	hdrLowestTrackableMicros  = 1
+	hdrHighestTrackableMicros = int64(60 * time.Second / time.Microsecond)
+	hdrSignificantFigures     = 3
+)
+
+type hdrShard struct {
+	mu   sync.Mutex
+	hist *hdrhistogram.Histogram
+}
+
+func newHDRShard() *hdrShard {
+	return &hdrShard{hist: hdrhistogram.New(hdrLowestTrackableMicros, hdrHighestTrackableMicros, hdrSignificantFigures)}
+}
+
+// latencyHDR is a sharded HDR histogram recording every request's latency
+// in microseconds. Unlike latencyHistogram's fixed Prometheus buckets, it
+// keeps enough resolution to report accurate high percentiles (p99.9,
+// p99.99) at millions of samples in bounded memory, and to export a
+// standard .hgrm percentile file.
+type latencyHDR struct {
+	shards []*hdrShard
+	next   uint64
+}
+
+func newLatencyHDR() *latencyHDR {
+	shards := make([]*hdrShard, hdrShardCount)
+	for i := range shards {
+		shards[i] = newHDRShard()
+	}
+	return &latencyHDR{shards: shards}
+}
+
+func (h *latencyHDR) shard() *hdrShard {
+	idx := atomic.AddUint64(&h.next, 1) % uint64(len(h.shards))
+	return h.shards[idx]
+}
+
+// observe records d as-is. Use this where there's no intended dispatch
+// schedule to correct against, e.g. the closed-model loop running
+// unthrottled.
+func (h *latencyHDR) observe(d time.Duration) {
+	s := h.shard()
+	s.mu.Lock()
+	s.hist.RecordValue(d.Microseconds())
+	s.mu.Unlock()
+}
+
+// observeCorrected records d plus synthetic samples for any arrival slots
+// that were missed while a prior request was still being served -
+// RecordCorrectedValue backfills them at expectedInterval steps below d,
+// which is how HdrHistogram corrects for coordinated omission: without
+// it, a stalled server looks fast because the samples it would have
+// produced during the stall were never taken.
+func (h *latencyHDR) observeCorrected(d, expectedInterval time.Duration) {
+	s := h.shard()
+	s.mu.Lock()
+	s.hist.RecordCorrectedValue(d.Microseconds(), expectedInterval.Microseconds())
+	s.mu.Unlock()
+}
+
+// merged combines every shard into one Histogram. Only call this once
+// observe/observeCorrected calls have finished - Merge isn't safe to race
+// against concurrent RecordValue calls on the same shard.
+func (h *latencyHDR) merged() *hdrhistogram.Histogram {
+	out := hdrhistogram.New(hdrLowestTrackableMicros, hdrHighestTrackableMicros, hdrSignificantFigures)
+	for _, s := range h.shards {
+		s.mu.Lock()
+		out.Merge(s.hist)
+		s.mu.Unlock()
+	}
+	return out
+}
+
+// hdrPercentileSpine is the console/JSON percentile summary this replaces
+// tachymeter's mean/min/max with: a fixed spine of latency percentiles
+// plus the observed maximum.
+type hdrPercentileSpine struct {
+	P50   time.Duration
+	P75   time.Duration
+	P90   time.Duration
+	P99   time.Duration
+	P999  time.Duration
+	P9999 time.Duration
+	Max   time.Duration
+}
+
+func newHDRPercentileSpine(hist *hdrhistogram.Histogram) hdrPercentileSpine {
+	at := func(p float64) time.Duration {
+		return time.Duration(hist.ValueAtPercentile(p)) * time.Microsecond
+	}
+	return hdrPercentileSpine{
+		P50:   at(50),
+		P75:   at(75),
+		P90:   at(90),
+		P99:   at(99),
+		P999:  at(99.9),
+		P9999: at(99.99),
+		Max:   time.Duration(hist.Max()) * time.Microsecond,
+	}
+}
+
+// writeHgrm exports hist in the standard HdrHistogram percentile-file
+// format (openable by the HdrHistogram plotter and other HDR tooling),
+// scaling the microsecond-valued histogram to milliseconds.
+func writeHgrm(hist *hdrhistogram.Histogram, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create hgrm file %q: %w", path, err)
+	}
+	defer f.Close()
+
+	if _, err := hist.PercentilesPrint(f, 5, 1000.0); err != nil {
+		return fmt.Errorf("failed to write hgrm file %q: %w", path, err)
+	}
+	return nil
+}
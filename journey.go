@@ -0,0 +1,295 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// JourneyStep is one ordered request within a `journeys:` entry. Steps run
+// sequentially for a single virtual user; values captured by an earlier
+// step's Capture rules become available to later steps as {{name}}
+// placeholders in Query and Variables, alongside the existing
+// {{random.*}} placeholders replaceRandomPlaceholders handles.
+type JourneyStep struct {
+	Name        string                 `yaml:"name"`
+	Query       string                 `yaml:"query"`
+	Variables   map[string]interface{} `yaml:"variables"`
+	Headers     map[string]string      `yaml:"headers"`
+	Capture     []CaptureRule          `yaml:"capture"`
+	ThinkTimeMS int                    `yaml:"think_time_ms"`
+	StopOnError bool                   `yaml:"stop_on_error"`
+}
+
+// CaptureRule pulls one value out of a step's response body, either via a
+// dotted JSONPath (e.g. "data.login.token", an optional leading "$." is
+// trimmed) or the first capture group of a regex against the raw body, and
+// stores it under Into for later steps to reference as {{Into}}.
+type CaptureRule struct {
+	Into     string `yaml:"into"`
+	JSONPath string `yaml:"json_path"`
+	Regex    string `yaml:"regex"`
+}
+
+// Journey is one entry in the `journeys:` list: a named, ordered script a
+// virtual user runs start to finish, instead of firing one mutation
+// repeatedly. When journeys are configured they replace scenario-mixing
+// for the run; a worker samples one journey (weighted like scenarios) per
+// iteration and walks it to completion.
+type Journey struct {
+	Name   string        `yaml:"name"`
+	Weight int           `yaml:"weight"`
+	Steps  []JourneyStep `yaml:"steps"`
+}
+
+var capturedPlaceholderRe = regexp.MustCompile(`\{\{([a-zA-Z_][a-zA-Z0-9_.]*)\}\}`)
+
+// injectCapturedVariables substitutes {{name}} in text with a previously
+// captured value. {{random.*}} placeholders are left untouched for
+// replaceRandomPlaceholders to expand afterwards.
+func injectCapturedVariables(text string, captured map[string]interface{}) string {
+	return capturedPlaceholderRe.ReplaceAllStringFunc(text, func(match string) string {
+		name := match[2 : len(match)-2]
+		if strings.HasPrefix(name, "random.") {
+			return match
+		}
+		if v, ok := captured[name]; ok {
+			return fmt.Sprintf("%v", v)
+		}
+		return match
+	})
+}
+
+// injectCapturedVariablesDeep applies injectCapturedVariables to every
+// string reachable inside value, mirroring processRandomValue's recursion
+// over the same map[string]interface{}/[]interface{} shapes.
+func injectCapturedVariablesDeep(value interface{}, captured map[string]interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		return injectCapturedVariables(v, captured)
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for k, val := range v {
+			result[k] = injectCapturedVariablesDeep(val, captured)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, val := range v {
+			result[i] = injectCapturedVariablesDeep(val, captured)
+		}
+		return result
+	default:
+		return value
+	}
+}
+
+// extract pulls this rule's value out of a step's raw response body.
+func (c CaptureRule) extract(body []byte) (interface{}, bool) {
+	if c.Regex != "" {
+		re, err := regexp.Compile(c.Regex)
+		if err != nil {
+			return nil, false
+		}
+		match := re.FindStringSubmatch(string(body))
+		switch len(match) {
+		case 0:
+			return nil, false
+		case 1:
+			return match[0], true
+		default:
+			return match[1], true
+		}
+	}
+
+	if c.JSONPath == "" {
+		return nil, false
+	}
+
+	var decoded interface{}
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		return nil, false
+	}
+
+	path := strings.TrimPrefix(strings.TrimPrefix(c.JSONPath, "$"), ".")
+	return walkJSONPath(decoded, path)
+}
+
+// walkJSONPath descends a decoded JSON value one dotted segment at a time,
+// supporting a trailing [n] array index on a segment (e.g. "items[0]").
+func walkJSONPath(node interface{}, path string) (interface{}, bool) {
+	if path == "" {
+		return node, true
+	}
+
+	segment, rest, _ := strings.Cut(path, ".")
+
+	key := segment
+	index := -1
+	if open := strings.IndexByte(segment, '['); open >= 0 && strings.HasSuffix(segment, "]") {
+		key = segment[:open]
+		parsed, err := strconv.Atoi(segment[open+1 : len(segment)-1])
+		if err != nil {
+			return nil, false
+		}
+		index = parsed
+	}
+
+	m, ok := node.(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	next, ok := m[key]
+	if !ok {
+		return nil, false
+	}
+
+	if index >= 0 {
+		arr, ok := next.([]interface{})
+		if !ok || index >= len(arr) {
+			return nil, false
+		}
+		next = arr[index]
+	}
+
+	return walkJSONPath(next, rest)
+}
+
+// runJourney walks one virtual user through journey's steps in order. Each
+// step's outcome is folded into the matching entry of stepStats (parallel
+// to journey.Steps), and values captured from a step's response become
+// available to later steps via {{name}} placeholders. A step with
+// StopOnError set halts the remaining steps once it fails.
+func runJourney(ctx context.Context, client *http.Client, config *Config, journey *Journey, stepStats []*ScenarioStats, authHeader, authValue string, logger *AsyncLogger) []RequestResult {
+	results := make([]RequestResult, 0, len(journey.Steps))
+	captured := make(map[string]interface{})
+
+	for i, step := range journey.Steps {
+		if ctx.Err() != nil {
+			break
+		}
+
+		query := injectCapturedVariables(step.Query, captured)
+
+		var variables map[string]interface{}
+		if step.Variables != nil {
+			withCaptures := injectCapturedVariablesDeep(step.Variables, captured).(map[string]interface{})
+			variables = generateRandomVariables(withCaptures)
+		}
+
+		headers := config.Headers
+		if step.Headers != nil {
+			merged := make(map[string]string, len(config.Headers)+len(step.Headers))
+			for k, v := range config.Headers {
+				merged[k] = v
+			}
+			for k, v := range step.Headers {
+				merged[k] = v
+			}
+			headers = merged
+		}
+
+		payload := GraphQLRequest{Query: query, Variables: variables}
+		payloadBytes, err := json.Marshal(payload)
+		if err != nil {
+			break
+		}
+
+		reqCtx, cancel := context.WithTimeout(ctx, config.RequestTimeout)
+		result := journeyRequest(reqCtx, client, config.URL, payloadBytes, authHeader, authValue, headers)
+		cancel()
+
+		if logger != nil && logger.IsEnabled() {
+			logger.LogRequest(result.StatusCode, result.RequestBody, result.ResponseBody)
+		}
+
+		if i < len(stepStats) {
+			stepStats[i].record(result)
+		}
+		results = append(results, result)
+
+		for _, rule := range step.Capture {
+			if v, ok := rule.extract([]byte(result.ResponseBody)); ok {
+				captured[rule.Into] = v
+			}
+		}
+
+		if !result.Success && step.StopOnError {
+			break
+		}
+
+		if step.ThinkTimeMS > 0 {
+			select {
+			case <-time.After(time.Duration(step.ThinkTimeMS) * time.Millisecond):
+			case <-ctx.Done():
+				return results
+			}
+		}
+	}
+
+	return results
+}
+
+// journeyRequest is makeRequest's GraphQL-over-HTTP logic, with one
+// difference: it always retains the response body regardless of
+// logRequests, since Capture rules need it whether or not request logging
+// is enabled.
+func journeyRequest(ctx context.Context, client *http.Client, url string, payload []byte, authHeader, authValue string, headers map[string]string) RequestResult {
+	start := time.Now()
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(payload))
+	if err != nil {
+		return RequestResult{Duration: time.Since(start), Error: err, Success: false}
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if authValue != "" {
+		req.Header.Set(authHeader, authValue)
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return RequestResult{Duration: time.Since(start), Error: err, Success: false}
+	}
+	defer resp.Body.Close()
+
+	duration := time.Since(start)
+	body, _ := io.ReadAll(resp.Body)
+
+	var gqlResp GraphQLResponse
+	success := resp.StatusCode == 200
+	if success {
+		if err := json.Unmarshal(body, &gqlResp); err == nil {
+			success = len(gqlResp.Errors) == 0
+		}
+	}
+
+	return RequestResult{
+		Duration:     duration,
+		StatusCode:   resp.StatusCode,
+		Success:      success,
+		RequestBody:  string(payload),
+		ResponseBody: string(body),
+	}
+}
+
+// flattenJourneyStats collapses the per-journey slices of per-step stats
+// into one flat list, the shape TestResults.JourneySteps and the
+// print/save routines expect.
+func flattenJourneyStats(perJourney [][]*ScenarioStats) []*ScenarioStats {
+	var flat []*ScenarioStats
+	for _, stats := range perJourney {
+		flat = append(flat, stats...)
+	}
+	return flat
+}
@@ -2,16 +2,20 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"math"
+	mathrand "math/rand"
 	"net/http"
 	"os"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	hdrhistogram "github.com/HdrHistogram/hdrhistogram-go"
 	"github.com/jamiealquiza/tachymeter"
 	"github.com/schollz/progressbar/v3"
 	"golang.org/x/time/rate"
@@ -30,22 +34,34 @@ const (
 )
 
 type Config struct {
-	URL           string
-	Mutation      string
-	AuthHeader    string
-	AuthValue     string
-	BaseAuthValue string
-	Headers       map[string]string
-	Concurrency   int
-	TotalReqs     int
-	TargetRPS     int
-	DurationSec   int
-	BaseVariables map[string]interface{}
-	ShowProgress  bool
-	SaveResults   bool
-	OutputDir     string
-	LogRequests   bool
-	LogFile       string
+	URL            string
+	Mutation       string
+	AuthHeader     string
+	AuthValue      string
+	BaseAuthValue  string
+	Headers        map[string]string
+	Concurrency    int
+	TotalReqs      int
+	TargetRPS      int
+	DurationSec    int
+	BaseVariables  map[string]interface{}
+	ShowProgress   bool
+	SaveResults    bool
+	OutputDir      string
+	LogRequests    bool
+	LogFile        string
+	LogSinks       []SinkConfig
+	Transport      string
+	Subscription   SubscriptionConfig
+	Warmup         WarmupConfig
+	Scenarios      []Scenario
+	Journeys       []Journey
+	TLS            *tls.Config
+	RequestTimeout time.Duration
+	MetricsListen  string
+	REST           RESTConfig
+	GRPC           GRPCConfig
+	LoadProfile    *LoadProfile
 }
 
 type EnvConfig struct {
@@ -55,24 +71,36 @@ type EnvConfig struct {
 		Header string `yaml:"header"`
 		Value  string `yaml:"value"`
 	} `yaml:"auth"`
+	TLS TLSConfig `yaml:"tls"`
 }
 
 type MutationConfig struct {
-	Name         string                    `yaml:"name"`
-	Description  string                    `yaml:"description"`
-	Environments map[string]EnvConfig      `yaml:"environments"`
-	Query        string                    `yaml:"query"`
-	Variables    map[string]interface{}    `yaml:"variables"`
-	Headers      map[string]string         `yaml:"headers"`
+	Name         string                 `yaml:"name"`
+	Description  string                 `yaml:"description"`
+	Environments map[string]EnvConfig   `yaml:"environments"`
+	Query        string                 `yaml:"query"`
+	Variables    map[string]interface{} `yaml:"variables"`
+	Headers      map[string]string      `yaml:"headers"`
+	Transport    string                 `yaml:"transport"`
+	Subscription SubscriptionConfig     `yaml:"subscription"`
+	Warmup       WarmupConfig           `yaml:"warmup"`
+	Scenarios    []Scenario             `yaml:"scenarios"`
+	Journeys     []Journey              `yaml:"journeys"`
+	Metrics      MetricsConfig          `yaml:"metrics"`
+	REST         RESTConfig             `yaml:"rest"`
+	GRPC         GRPCConfig             `yaml:"grpc"`
 	Load         struct {
-		Concurrency   int `yaml:"concurrency"`
-		Requests      int `yaml:"requests"`
-		RPS           int `yaml:"rps"`
-		DurationSec   int `yaml:"duration_seconds"`
+		Concurrency       int         `yaml:"concurrency"`
+		Requests          int         `yaml:"requests"`
+		RPS               int         `yaml:"rps"`
+		DurationSec       int         `yaml:"duration_seconds"`
+		RequestTimeoutSec int         `yaml:"request_timeout_seconds"`
+		Profile           LoadProfile `yaml:"profile"`
 	} `yaml:"load"`
 	Logging struct {
-		Enabled bool   `yaml:"enabled"`
-		LogFile string `yaml:"file"`
+		Enabled bool         `yaml:"enabled"`
+		LogFile string       `yaml:"file"`
+		Sinks   []SinkConfig `yaml:"sinks"`
 	} `yaml:"logging"`
 }
 
@@ -89,29 +117,74 @@ type GraphQLResponse struct {
 }
 
 type RequestResult struct {
-	Duration     time.Duration
-	StatusCode   int
-	Error        error
-	Success      bool
-	RequestBody  string
-	ResponseBody string
+	Duration              time.Duration
+	StatusCode            int
+	Error                 error
+	Success               bool
+	RequestBody           string
+	ResponseBody          string
+	Messages              int             // websocketProtocol only: subscription messages received
+	Disconnected          bool            // websocketProtocol only: connection dropped before completion
+	InterMessageLatencies []time.Duration // websocketProtocol only: gaps between successive `next` frames
 }
 
 type TestResults struct {
-	Metrics        *tachymeter.Metrics
-	TotalRequests  int
-	SuccessfulReqs int
-	FailedReqs     int
-	StatusCodes    map[int]int
+	Metrics          *tachymeter.Metrics
+	TotalRequests    int
+	SuccessfulReqs   int
+	FailedReqs       int
+	StatusCodes      map[int]int
+	Subscription     *SubscriptionMetrics
+	WallTime         time.Duration
+	Scenarios        []*ScenarioStats
+	JourneySteps     []*ScenarioStats
+	SchedulingLag    *tachymeter.Metrics
+	Scheduled        int64
+	Dispatched       int64
+	MissedDeadline   int64
+	LatencyHDR       *latencyHDR
+	LatencyCorrected bool // whether LatencyHDR's samples went through coordinated-omission correction
 }
 
 type ResultsOutput struct {
-	Timestamp   string            `json:"timestamp"`
-	TestConfig  TestConfigSummary `json:"test_config"`
-	Summary     ResultsSummary    `json:"summary"`
-	Latency     LatencyMetrics    `json:"latency"`
-	Percentiles PercentileMetrics `json:"percentiles"`
-	StatusCodes map[int]int       `json:"status_codes"`
+	Timestamp    string              `json:"timestamp"`
+	TestConfig   TestConfigSummary   `json:"test_config"`
+	Summary      ResultsSummary      `json:"summary"`
+	Latency      LatencyMetrics      `json:"latency"`
+	Percentiles  PercentileMetrics   `json:"percentiles"`
+	StatusCodes  map[int]int         `json:"status_codes"`
+	Subscription *SubscriptionOutput `json:"subscription,omitempty"`
+	Scenarios    []ScenarioOutput    `json:"scenarios,omitempty"`
+	JourneySteps []ScenarioOutput    `json:"journey_steps,omitempty"`
+	Scheduling   *SchedulingOutput   `json:"scheduling,omitempty"`
+	HgrmFile     string              `json:"hgrm_file,omitempty"`
+}
+
+// SchedulingOutput reports coordinated-omission numbers for an open-model
+// run: how far actual dispatch time drifted from the profile's intended
+// timeline, and how many requests drifted past openModelLagWarnThreshold.
+type SchedulingOutput struct {
+	Scheduled      int64  `json:"scheduled"`
+	Dispatched     int64  `json:"dispatched"`
+	MissedDeadline int64  `json:"missed_deadline"`
+	LagP95         string `json:"lag_p95"`
+	LagMax         string `json:"lag_max"`
+}
+
+type ScenarioOutput struct {
+	Name           string  `json:"name"`
+	TotalRequests  int     `json:"total_requests"`
+	SuccessfulReqs int     `json:"successful_requests"`
+	FailedReqs     int     `json:"failed_requests"`
+	RequestsPerSec float64 `json:"requests_per_second"`
+	P95            string  `json:"p95"`
+}
+
+type SubscriptionOutput struct {
+	MessagesPerSec  float64 `json:"messages_per_second"`
+	FirstMessageP95 string  `json:"first_message_p95"`
+	InterMessageP95 string  `json:"inter_message_p95"`
+	Disconnects     int     `json:"disconnects"`
 }
 
 type TestConfigSummary struct {
@@ -140,26 +213,42 @@ type LatencyMetrics struct {
 }
 
 type PercentileMetrics struct {
-	P50  string `json:"p50"`
-	P75  string `json:"p75"`
-	P95  string `json:"p95"`
-	P99  string `json:"p99"`
-	P999 string `json:"p999"`
+	P50   string `json:"p50"`
+	P75   string `json:"p75"`
+	P90   string `json:"p90"`
+	P95   string `json:"p95"`
+	P99   string `json:"p99"`
+	P999  string `json:"p999"`
+	P9999 string `json:"p9999"`
+	Max   string `json:"max"`
 }
 
 var (
 	gracefulShutdown int32
 	testResults      *TestResults
+	testResultsMu    sync.Mutex
 	testConfig       *Config
+	runCancel        context.CancelFunc
 )
 
 func main() {
 	var configFile string
 	var environment string
+	var mode string
+	var workerListen string
+	var coordinatorWorkers string
 	flag.StringVar(&configFile, "config", "", "Path to YAML configuration file (required)")
 	flag.StringVar(&environment, "env", "", "Environment to use from config file (required)")
+	flag.StringVar(&mode, "mode", "standalone", "Run mode: standalone, coordinator, or worker")
+	flag.StringVar(&workerListen, "worker-listen", ":7070", "Address the gRPC worker service listens on (worker mode)")
+	flag.StringVar(&coordinatorWorkers, "workers", "", "Comma-separated worker addresses (coordinator mode)")
 	flag.Parse()
 
+	if mode == "worker" {
+		runWorkerMode(workerListen)
+		return
+	}
+
 	if configFile == "" {
 		fmt.Printf("%sError: Config file is required%s\n", ColorRed, ColorReset)
 		flag.Usage()
@@ -199,7 +288,16 @@ func main() {
 	}
 	fmt.Println()
 
-	results := runLoadTest(config)
+	var results *TestResults
+	if mode == "coordinator" {
+		if config.Concurrency <= 0 {
+			config.Concurrency = 10
+		}
+		workerAddrs := strings.Split(coordinatorWorkers, ",")
+		results = runCoordinator(config, workerAddrs)
+	} else {
+		results = runLoadTest(config)
+	}
 	testResults = results
 	printResults(results)
 
@@ -211,30 +309,88 @@ func main() {
 }
 
 func runLoadTest(config *Config) *TestResults {
+	if config.LoadProfile != nil && len(config.LoadProfile.Stages) > 0 {
+		return runOpenModelLoadTest(config)
+	}
+
 	windowSize := 10000
 	if config.TotalReqs < windowSize {
 		windowSize = config.TotalReqs
 	}
 
+	var warmupResults []RequestResult
 	if config.TargetRPS > 0 && config.Concurrency <= 0 {
-		estP95 := 300 * time.Millisecond // adjust if you know better; or do a quick warm-up probe
-		config.Concurrency = int(math.Ceil(float64(config.TargetRPS) * estP95.Seconds()))
-		if config.Concurrency < 1 {
-			config.Concurrency = 1
+		if config.Warmup.Enabled {
+			config.Concurrency, warmupResults = runWarmup(config)
+		} else {
+			estP95 := 300 * time.Millisecond // no warmup configured; fall back to a generic guess
+			config.Concurrency = int(math.Ceil(float64(config.TargetRPS) * estP95.Seconds()))
+			if config.Concurrency < 1 {
+				config.Concurrency = 1
+			}
+			fmt.Printf("%sderived concurrency:%s %d (from %d rps @ ~%s p95)\n",
+				ColorBlue, ColorReset, config.Concurrency, config.TargetRPS, estP95)
 		}
-		fmt.Printf("%sderived concurrency:%s %d (from %d rps @ ~%s p95)\n",
-			ColorBlue, ColorReset, config.Concurrency, config.TargetRPS, estP95)
 	}
 
 	t := tachymeter.New(&tachymeter.Config{Size: windowSize})
 
-	var mu sync.Mutex
+	mu := &testResultsMu
 	var wg sync.WaitGroup
 
 	successCount := 0
 	failedCount := 0
 	statusCodes := make(map[int]int)
 
+	var subMetrics *SubscriptionMetrics
+	if config.Transport == "websocket" {
+		subMetrics = &SubscriptionMetrics{
+			FirstMessage: tachymeter.New(&tachymeter.Config{Size: windowSize}),
+			InterMessage: tachymeter.New(&tachymeter.Config{Size: windowSize}),
+		}
+	}
+
+	var scenarioSampler *aliasSampler
+	var scenarioStats []*ScenarioStats
+	if len(config.Scenarios) > 0 {
+		weights := make([]int, len(config.Scenarios))
+		scenarioStats = make([]*ScenarioStats, len(config.Scenarios))
+		for i, sc := range config.Scenarios {
+			w := sc.Weight
+			if w <= 0 {
+				w = 1
+			}
+			weights[i] = w
+			scenarioStats[i] = newScenarioStats(sc.Name, windowSize)
+		}
+		scenarioSampler = newAliasSampler(weights)
+	}
+
+	var journeySampler *aliasSampler
+	var journeyStepStats [][]*ScenarioStats
+	if len(config.Journeys) > 0 {
+		weights := make([]int, len(config.Journeys))
+		journeyStepStats = make([][]*ScenarioStats, len(config.Journeys))
+		for i, j := range config.Journeys {
+			w := j.Weight
+			if w <= 0 {
+				w = 1
+			}
+			weights[i] = w
+
+			stepStats := make([]*ScenarioStats, len(j.Steps))
+			for si, step := range j.Steps {
+				name := step.Name
+				if name == "" {
+					name = fmt.Sprintf("%s/step%d", j.Name, si+1)
+				}
+				stepStats[si] = newScenarioStats(name, windowSize)
+			}
+			journeyStepStats[i] = stepStats
+		}
+		journeySampler = newAliasSampler(weights)
+	}
+
 	var bar *progressbar.ProgressBar
 	var progressMu sync.Mutex
 	var completedRequests int64
@@ -246,6 +402,7 @@ func runLoadTest(config *Config) *TestResults {
 		MaxConnsPerHost:     config.Concurrency * 2,
 		ForceAttemptHTTP2:   true,
 		DisableCompression:  true,
+		TLSClientConfig:     config.TLS,
 	}
 	client := &http.Client{
 		Timeout:   30 * time.Second,
@@ -254,6 +411,49 @@ func runLoadTest(config *Config) *TestResults {
 
 	wallTimeStart := time.Now()
 
+	histogram := newLatencyHistogram()
+	if config.MetricsListen != "" {
+		metricsServer := startMetricsServer(config.MetricsListen, histogram, wallTimeStart, config)
+		defer metricsServer.Close()
+	}
+
+	hdr := newLatencyHDR()
+	var hdrInterval time.Duration
+	if config.TargetRPS > 0 {
+		hdrInterval = time.Second / time.Duration(config.TargetRPS)
+	}
+
+	for _, result := range warmupResults {
+		t.AddTime(result.Duration)
+		histogram.observe(result.Duration)
+		if hdrInterval > 0 {
+			hdr.observeCorrected(result.Duration, hdrInterval)
+		} else {
+			hdr.observe(result.Duration)
+		}
+
+		if result.Success {
+			successCount++
+		} else {
+			failedCount++
+		}
+		statusCodes[result.StatusCode]++
+	}
+
+	protocol, err := newProtocol(protocolConfig{
+		Transport:      config.Transport,
+		URL:            config.URL,
+		REST:           config.REST,
+		GRPC:           config.GRPC,
+		Subscription:   config.Subscription,
+		RequestTimeout: config.RequestTimeout,
+	}, client)
+	if err != nil {
+		fmt.Printf("%s%v%s\n", ColorRed, err, ColorReset)
+		return &TestResults{}
+	}
+	defer protocol.Close()
+
 	semaphore := make(chan struct{}, config.Concurrency)
 
 	if config.ShowProgress {
@@ -276,8 +476,17 @@ func runLoadTest(config *Config) *TestResults {
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
+	runCancel = cancel
 
-	defer cancel()
+	if config.DurationSec > 0 {
+		go func() {
+			select {
+			case <-time.After(time.Duration(config.DurationSec) * time.Second):
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+	}
 
 	var logger *AsyncLogger
 
@@ -287,7 +496,7 @@ func runLoadTest(config *Config) *TestResults {
 			fmt.Printf("%sWarning: Failed to create output directory for logs: %v%s\n", ColorYellow, err, ColorReset)
 		} else {
 			var err error
-			logger, err = NewAsyncLogger(config.LogRequests, config.LogFile)
+			logger, err = NewAsyncLogger(config.LogRequests, config.LogFile, config.LogSinks)
 			if err != nil {
 				fmt.Printf("%sWarning: Failed to create logger: %v%s\n", ColorYellow, err, ColorReset)
 				config.LogRequests = false
@@ -335,9 +544,89 @@ func runLoadTest(config *Config) *TestResults {
 				return
 			}
 
+			atomic.AddInt64(&inflightRequests, 1)
+			defer atomic.AddInt64(&inflightRequests, -1)
+
+			if journeySampler != nil {
+				idx := journeySampler.sample(mathrand.Float64(), mathrand.Float64())
+				journey := &config.Journeys[idx]
+
+				authValue := config.BaseAuthValue
+				if authValue != "" {
+					processed := replaceRandomPlaceholders(authValue)
+					if str, ok := processed.(string); ok {
+						authValue = str
+					} else {
+						authValue = fmt.Sprintf("%v", processed)
+					}
+				}
+
+				journeyResults := runJourney(ctx, client, config, journey, journeyStepStats[idx], config.AuthHeader, authValue, logger)
+
+				for _, result := range journeyResults {
+					t.AddTime(result.Duration)
+					histogram.observe(result.Duration)
+					hdr.observe(result.Duration)
+
+					mu.Lock()
+					if result.Success {
+						successCount++
+					} else {
+						failedCount++
+					}
+					statusCodes[result.StatusCode]++
+
+					testResults = &TestResults{
+						Metrics:        t.Calc(),
+						TotalRequests:  successCount + failedCount,
+						SuccessfulReqs: successCount,
+						FailedReqs:     failedCount,
+						StatusCodes:    statusCodes,
+						Subscription:   subMetrics,
+						WallTime:       time.Since(wallTimeStart),
+						Scenarios:      scenarioStats,
+						JourneySteps:   flattenJourneyStats(journeyStepStats),
+						LatencyHDR:     hdr,
+					}
+					mu.Unlock()
+				}
+
+				if config.ShowProgress {
+					progressMu.Lock()
+					completedRequests++
+					bar.Set64(completedRequests)
+					progressMu.Unlock()
+				}
+				return
+			}
+
+			query := config.Mutation
+			baseVariables := config.BaseVariables
+			requestHeaders := config.Headers
+			scenarioIdx := -1
+
+			if scenarioSampler != nil {
+				scenarioIdx = scenarioSampler.sample(mathrand.Float64(), mathrand.Float64())
+				sc := config.Scenarios[scenarioIdx]
+				query = sc.Query
+				if sc.Variables != nil {
+					baseVariables = sc.Variables
+				}
+				if sc.Headers != nil {
+					merged := make(map[string]string, len(config.Headers)+len(sc.Headers))
+					for k, v := range config.Headers {
+						merged[k] = v
+					}
+					for k, v := range sc.Headers {
+						merged[k] = v
+					}
+					requestHeaders = merged
+				}
+			}
+
 			var variables map[string]interface{}
-			if config.BaseVariables != nil {
-				variables = generateRandomVariables(config.BaseVariables)
+			if baseVariables != nil {
+				variables = generateRandomVariables(baseVariables)
 			}
 
 			authValue := config.BaseAuthValue
@@ -350,39 +639,73 @@ func runLoadTest(config *Config) *TestResults {
 				}
 			}
 
-			payload := GraphQLRequest{
-				Query:     config.Mutation,
-				Variables: variables,
+			result := protocol.Do(ctx, protocolRequest{
+				Query:       query,
+				Variables:   variables,
+				Headers:     requestHeaders,
+				AuthHeader:  config.AuthHeader,
+				AuthValue:   authValue,
+				LogRequests: config.LogRequests,
+			})
+
+			if logger != nil && logger.IsEnabled() && config.Transport != "websocket" && config.Transport != "grpc" {
+				logger.LogRequest(result.StatusCode, result.RequestBody, result.ResponseBody)
 			}
 
-			payloadBytes, err := json.Marshal(payload)
-			if err != nil {
-				fmt.Printf("%sError marshaling payload: %v%s\n", ColorRed, err, ColorReset)
-				return
+			if config.Transport == "websocket" {
+				mu.Lock()
+				subMetrics.TotalMessages += result.Messages
+				if result.Messages > 0 {
+					subMetrics.FirstMessage.AddTime(result.Duration)
+				}
+				for _, gap := range result.InterMessageLatencies {
+					subMetrics.InterMessage.AddTime(gap)
+				}
+				if result.Disconnected {
+					subMetrics.Disconnects++
+				}
+				mu.Unlock()
 			}
 
-			result := makeRequest(client, config.URL, payloadBytes, config.AuthHeader, authValue, config.Headers, config.LogRequests)
+			duration := result.Duration
+			statusCode := result.StatusCode
+			success := result.Success
 
-			if logger != nil && logger.IsEnabled() {
-				logger.LogRequest(result.StatusCode, result.RequestBody, result.ResponseBody)
+			if scenarioIdx >= 0 {
+				scenarioStats[scenarioIdx].record(RequestResult{
+					Duration:   duration,
+					StatusCode: statusCode,
+					Success:    success,
+				})
 			}
 
-			t.AddTime(result.Duration)
+			t.AddTime(duration)
+			histogram.observe(duration)
+			if hdrInterval > 0 {
+				hdr.observeCorrected(duration, hdrInterval)
+			} else {
+				hdr.observe(duration)
+			}
 
 			mu.Lock()
-			if result.Success {
+			if success {
 				successCount++
 			} else {
 				failedCount++
 			}
-			statusCodes[result.StatusCode]++
+			statusCodes[statusCode]++
 
 			testResults = &TestResults{
-				Metrics:        t.Calc(),
-				TotalRequests:  successCount + failedCount,
-				SuccessfulReqs: successCount,
-				FailedReqs:     failedCount,
-				StatusCodes:    statusCodes,
+				Metrics:          t.Calc(),
+				TotalRequests:    successCount + failedCount,
+				SuccessfulReqs:   successCount,
+				FailedReqs:       failedCount,
+				StatusCodes:      statusCodes,
+				Subscription:     subMetrics,
+				WallTime:         time.Since(wallTimeStart),
+				Scenarios:        scenarioStats,
+				LatencyHDR:       hdr,
+				LatencyCorrected: hdrInterval > 0,
 			}
 			mu.Unlock()
 
@@ -409,11 +732,16 @@ func runLoadTest(config *Config) *TestResults {
 	fmt.Printf("%stotal requests made: %s%d%s\n\n", ColorBlue, ColorBold, successCount+failedCount, ColorReset)
 
 	return &TestResults{
-		Metrics:        t.Calc(),
-		TotalRequests:  successCount + failedCount,
-		SuccessfulReqs: successCount,
-		FailedReqs:     failedCount,
-		StatusCodes:    statusCodes,
+		Metrics:          t.Calc(),
+		TotalRequests:    successCount + failedCount,
+		SuccessfulReqs:   successCount,
+		FailedReqs:       failedCount,
+		StatusCodes:      statusCodes,
+		Subscription:     subMetrics,
+		WallTime:         wallTime,
+		Scenarios:        scenarioStats,
+		LatencyHDR:       hdr,
+		LatencyCorrected: hdrInterval > 0,
 	}
 }
 
@@ -453,12 +781,30 @@ func printResults(results *TestResults) {
 	fmt.Printf("%sstandard deviation:%s %s\n", ColorBlue, ColorReset, results.Metrics.Time.StdDev)
 	fmt.Println()
 
-	fmt.Printf("%s%s=== percentiles ===%s\n", ColorBold, ColorCyan, ColorReset)
-	fmt.Printf("%s50th percentile:%s    %s\n", ColorBlue, ColorReset, results.Metrics.Time.P50)
-	fmt.Printf("%s75th percentile:%s    %s\n", ColorBlue, ColorReset, results.Metrics.Time.P75)
-	fmt.Printf("%s95th percentile:%s    %s%s%s\n", ColorBlue, ColorReset, ColorYellow, results.Metrics.Time.P95, ColorReset)
-	fmt.Printf("%s99th percentile:%s    %s%s%s\n", ColorBlue, ColorReset, ColorRed, results.Metrics.Time.P99, ColorReset)
-	fmt.Printf("%s99.9th percentile:%s  %s%s%s\n", ColorBlue, ColorReset, ColorRed, results.Metrics.Time.P999, ColorReset)
+	switch {
+	case results.LatencyHDR != nil && results.LatencyCorrected:
+		fmt.Printf("%s%s=== percentiles (hdr, coordinated-omission corrected) ===%s\n", ColorBold, ColorCyan, ColorReset)
+	case results.LatencyHDR != nil:
+		fmt.Printf("%s%s=== percentiles (hdr) ===%s\n", ColorBold, ColorCyan, ColorReset)
+	default:
+		fmt.Printf("%s%s=== percentiles ===%s\n", ColorBold, ColorCyan, ColorReset)
+	}
+	if results.LatencyHDR != nil {
+		spine := newHDRPercentileSpine(results.LatencyHDR.merged())
+		fmt.Printf("%s50th percentile:%s    %s\n", ColorBlue, ColorReset, spine.P50)
+		fmt.Printf("%s75th percentile:%s    %s\n", ColorBlue, ColorReset, spine.P75)
+		fmt.Printf("%s90th percentile:%s    %s\n", ColorBlue, ColorReset, spine.P90)
+		fmt.Printf("%s99th percentile:%s    %s%s%s\n", ColorBlue, ColorReset, ColorYellow, spine.P99, ColorReset)
+		fmt.Printf("%s99.9th percentile:%s  %s%s%s\n", ColorBlue, ColorReset, ColorRed, spine.P999, ColorReset)
+		fmt.Printf("%s99.99th percentile:%s %s%s%s\n", ColorBlue, ColorReset, ColorRed, spine.P9999, ColorReset)
+		fmt.Printf("%smax:%s                %s%s%s\n", ColorBlue, ColorReset, ColorRed, spine.Max, ColorReset)
+	} else {
+		fmt.Printf("%s50th percentile:%s    %s\n", ColorBlue, ColorReset, results.Metrics.Time.P50)
+		fmt.Printf("%s75th percentile:%s    %s\n", ColorBlue, ColorReset, results.Metrics.Time.P75)
+		fmt.Printf("%s95th percentile:%s    %s%s%s\n", ColorBlue, ColorReset, ColorYellow, results.Metrics.Time.P95, ColorReset)
+		fmt.Printf("%s99th percentile:%s    %s%s%s\n", ColorBlue, ColorReset, ColorRed, results.Metrics.Time.P99, ColorReset)
+		fmt.Printf("%s99.9th percentile:%s  %s%s%s\n", ColorBlue, ColorReset, ColorRed, results.Metrics.Time.P999, ColorReset)
+	}
 	fmt.Println()
 
 	fmt.Printf("%s%s=== status codes ===%s\n", ColorBold, ColorCyan, ColorReset)
@@ -479,6 +825,59 @@ func printResults(results *TestResults) {
 		fmt.Printf("%s%d:%s %s%d (%.2f%%)%s\n", codeColor, code, ColorReset, codeColor, count, percentage, ColorReset)
 	}
 	fmt.Println()
+
+	if results.Subscription != nil {
+		msgsPerSec := 0.0
+		if results.WallTime > 0 {
+			msgsPerSec = float64(results.Subscription.TotalMessages) / results.WallTime.Seconds()
+		}
+		fmt.Printf("%s%s=== subscriptions ===%s\n", ColorBold, ColorCyan, ColorReset)
+		fmt.Printf("%smessages:%s           %d\n", ColorBlue, ColorReset, results.Subscription.TotalMessages)
+		fmt.Printf("%smsgs/sec:%s           %.2f\n", ColorBlue, ColorReset, msgsPerSec)
+		fmt.Printf("%sfirst-message p95:%s  %s\n", ColorBlue, ColorReset, results.Subscription.FirstMessage.Calc().Time.P95)
+		fmt.Printf("%sinter-message p95:%s  %s\n", ColorBlue, ColorReset, results.Subscription.InterMessage.Calc().Time.P95)
+		fmt.Printf("%sdisconnects:%s        %d\n", ColorBlue, ColorReset, results.Subscription.Disconnects)
+		fmt.Println()
+	}
+
+	if len(results.Scenarios) > 0 {
+		fmt.Printf("%s%s=== scenarios ===%s\n", ColorBold, ColorCyan, ColorReset)
+		for _, s := range results.Scenarios {
+			s.mu.Lock()
+			total := s.SuccessCount + s.FailedCount
+			metrics := s.Tachymeter.Calc()
+			s.mu.Unlock()
+
+			fmt.Printf("%s%s:%s %d requests, %s%.2f%%%s success, p95 %s\n",
+				ColorBlue, s.Name, ColorReset, total, ColorGreen,
+				float64(s.SuccessCount)/float64(total)*100, ColorReset, metrics.Time.P95)
+		}
+		fmt.Println()
+	}
+
+	if len(results.JourneySteps) > 0 {
+		fmt.Printf("%s%s=== journey steps ===%s\n", ColorBold, ColorCyan, ColorReset)
+		for _, s := range results.JourneySteps {
+			s.mu.Lock()
+			total := s.SuccessCount + s.FailedCount
+			metrics := s.Tachymeter.Calc()
+			s.mu.Unlock()
+
+			fmt.Printf("%s%s:%s %d requests, %s%.2f%%%s success, p95 %s\n",
+				ColorBlue, s.Name, ColorReset, total, ColorGreen,
+				float64(s.SuccessCount)/float64(total)*100, ColorReset, metrics.Time.P95)
+		}
+		fmt.Println()
+	}
+
+	if results.SchedulingLag != nil {
+		fmt.Printf("%s%s=== open-model scheduling ===%s\n", ColorBold, ColorCyan, ColorReset)
+		fmt.Printf("%sscheduled:%s        %d\n", ColorBlue, ColorReset, results.Scheduled)
+		fmt.Printf("%sdispatched:%s       %d\n", ColorBlue, ColorReset, results.Dispatched)
+		fmt.Printf("%smissed deadline:%s  %d\n", ColorBlue, ColorReset, results.MissedDeadline)
+		fmt.Printf("%sscheduling lag:%s   p95 %s, max %s\n", ColorBlue, ColorReset, results.SchedulingLag.Time.P95, results.SchedulingLag.Time.Max)
+		fmt.Println()
+	}
 }
 
 func saveResults(results *TestResults, config *Config) error {
@@ -527,15 +926,101 @@ func saveResults(results *TestResults, config *Config) error {
 		StatusCodes: results.StatusCodes,
 	}
 
+	var mergedHDR *hdrhistogram.Histogram
+	if results.LatencyHDR != nil {
+		mergedHDR = results.LatencyHDR.merged()
+		spine := newHDRPercentileSpine(mergedHDR)
+		output.Percentiles = PercentileMetrics{
+			P50:   spine.P50.String(),
+			P75:   spine.P75.String(),
+			P90:   spine.P90.String(),
+			P95:   results.Metrics.Time.P95.String(),
+			P99:   spine.P99.String(),
+			P999:  spine.P999.String(),
+			P9999: spine.P9999.String(),
+			Max:   spine.Max.String(),
+		}
+	}
+
+	if results.Subscription != nil {
+		msgsPerSec := 0.0
+		if results.WallTime > 0 {
+			msgsPerSec = float64(results.Subscription.TotalMessages) / results.WallTime.Seconds()
+		}
+		output.Subscription = &SubscriptionOutput{
+			MessagesPerSec:  msgsPerSec,
+			FirstMessageP95: results.Subscription.FirstMessage.Calc().Time.P95.String(),
+			InterMessageP95: results.Subscription.InterMessage.Calc().Time.P95.String(),
+			Disconnects:     results.Subscription.Disconnects,
+		}
+	}
+
+	for _, s := range results.Scenarios {
+		s.mu.Lock()
+		total := s.SuccessCount + s.FailedCount
+		metrics := s.Tachymeter.Calc()
+		s.mu.Unlock()
+
+		output.Scenarios = append(output.Scenarios, ScenarioOutput{
+			Name:           s.Name,
+			TotalRequests:  total,
+			SuccessfulReqs: s.SuccessCount,
+			FailedReqs:     s.FailedCount,
+			RequestsPerSec: metrics.Rate.Second,
+			P95:            metrics.Time.P95.String(),
+		})
+	}
+
+	for _, s := range results.JourneySteps {
+		s.mu.Lock()
+		total := s.SuccessCount + s.FailedCount
+		metrics := s.Tachymeter.Calc()
+		s.mu.Unlock()
+
+		output.JourneySteps = append(output.JourneySteps, ScenarioOutput{
+			Name:           s.Name,
+			TotalRequests:  total,
+			SuccessfulReqs: s.SuccessCount,
+			FailedReqs:     s.FailedCount,
+			RequestsPerSec: metrics.Rate.Second,
+			P95:            metrics.Time.P95.String(),
+		})
+	}
+
+	if results.SchedulingLag != nil {
+		output.Scheduling = &SchedulingOutput{
+			Scheduled:      results.Scheduled,
+			Dispatched:     results.Dispatched,
+			MissedDeadline: results.MissedDeadline,
+			LagP95:         results.SchedulingLag.Time.P95.String(),
+			LagMax:         results.SchedulingLag.Time.Max.String(),
+		}
+	}
+
 	jsonData, err := json.MarshalIndent(output, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal results to JSON: %w", err)
 	}
 
+	if mergedHDR != nil {
+		hgrmFilename := fmt.Sprintf("%s/loadtest_results_%s.hgrm", config.OutputDir, timestamp)
+		if err := writeHgrm(mergedHDR, hgrmFilename); err != nil {
+			fmt.Printf("%sWarning: Failed to write hgrm file: %v%s\n", ColorYellow, err, ColorReset)
+		} else {
+			output.HgrmFile = hgrmFilename
+			if jsonData, err = json.MarshalIndent(output, "", "  "); err != nil {
+				return fmt.Errorf("failed to marshal results to JSON: %w", err)
+			}
+		}
+	}
+
 	if err := os.WriteFile(filename, jsonData, 0644); err != nil {
 		return fmt.Errorf("failed to write results to file: %w", err)
 	}
 
 	fmt.Printf("%sresults saved to: %s%s%s\n", ColorGreen, ColorBold, filename, ColorReset)
+	if mergedHDR != nil {
+		fmt.Printf("%shgrm saved to: %s%s%s\n", ColorGreen, ColorBold, output.HgrmFile, ColorReset)
+	}
 	return nil
 }
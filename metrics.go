@@ -0,0 +1,167 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// MetricsConfig is the `metrics:` block of MutationConfig. When Listen is
+// set, runLoadTest starts an HTTP server before the load loop begins so
+// Grafana (or curl) can watch the run live instead of waiting for the
+// end-of-run summary.
+type MetricsConfig struct {
+	Listen string `yaml:"listen"`
+}
+
+var inflightRequests int64
+
+// latencyBucketBoundsSeconds are the Prometheus histogram bucket upper
+// bounds, spanning a typical web-request latency range.
+var latencyBucketBoundsSeconds = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// latencyHistogram is a mutex-protected Prometheus-style cumulative
+// histogram. Hot per-request counters (inflight, totals) use atomics
+// instead; this one is mutex-protected because a bucket lookup plus two
+// float accumulations isn't worth making lock-free.
+type latencyHistogram struct {
+	mu      sync.Mutex
+	buckets []uint64 // cumulative counts, parallel to latencyBucketBoundsSeconds, plus one +Inf bucket
+	sum     float64
+	count   uint64
+}
+
+func newLatencyHistogram() *latencyHistogram {
+	return &latencyHistogram{buckets: make([]uint64, len(latencyBucketBoundsSeconds)+1)}
+}
+
+func (h *latencyHistogram) observe(d time.Duration) {
+	seconds := d.Seconds()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.sum += seconds
+	h.count++
+
+	idx := sort.SearchFloat64s(latencyBucketBoundsSeconds, seconds)
+	for i := idx; i < len(h.buckets); i++ {
+		h.buckets[i]++
+	}
+}
+
+func (h *latencyHistogram) snapshot() (buckets []uint64, sum float64, count uint64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	buckets = make([]uint64, len(h.buckets))
+	copy(buckets, h.buckets)
+	return buckets, h.sum, h.count
+}
+
+// snapshotTestResults copies the package-level testResults under
+// testResultsMu, the same way the /metrics handler already copies
+// StatusCodes, so callers can encode or save it without racing the worker
+// goroutines that keep mutating the live StatusCodes map.
+func snapshotTestResults() *TestResults {
+	testResultsMu.Lock()
+	defer testResultsMu.Unlock()
+
+	if testResults == nil {
+		return nil
+	}
+
+	snapshot := *testResults
+	snapshot.StatusCodes = make(map[int]int, len(testResults.StatusCodes))
+	for code, count := range testResults.StatusCodes {
+		snapshot.StatusCodes[code] = count
+	}
+	return &snapshot
+}
+
+// startMetricsServer starts the opt-in /metrics, /live and /snapshot HTTP
+// endpoints in the background. It returns the *http.Server so the caller can
+// shut it down when the run finishes; a failed listen is logged, not fatal,
+// since a test run shouldn't be aborted over an observability endpoint.
+func startMetricsServer(addr string, histogram *latencyHistogram, wallTimeStart time.Time, config *Config) *http.Server {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		testResultsMu.Lock()
+		total := 0
+		statusCodes := make(map[int]int)
+		if testResults != nil {
+			total = testResults.TotalRequests
+			for code, count := range testResults.StatusCodes {
+				statusCodes[code] = count
+			}
+		}
+		testResultsMu.Unlock()
+
+		elapsed := time.Since(wallTimeStart).Seconds()
+		rps := 0.0
+		if elapsed > 0 {
+			rps = float64(total) / elapsed
+		}
+
+		buckets, sum, count := histogram.snapshot()
+
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+
+		fmt.Fprintf(w, "# HELP loadtest_requests_total Total completed requests by status code.\n")
+		fmt.Fprintf(w, "# TYPE loadtest_requests_total counter\n")
+		for code, c := range statusCodes {
+			fmt.Fprintf(w, "loadtest_requests_total{status=\"%d\"} %d\n", code, c)
+		}
+
+		fmt.Fprintf(w, "# HELP loadtest_inflight Requests currently in flight.\n")
+		fmt.Fprintf(w, "# TYPE loadtest_inflight gauge\n")
+		fmt.Fprintf(w, "loadtest_inflight %d\n", atomic.LoadInt64(&inflightRequests))
+
+		fmt.Fprintf(w, "# HELP loadtest_rps Overall requests per second since the run started.\n")
+		fmt.Fprintf(w, "# TYPE loadtest_rps gauge\n")
+		fmt.Fprintf(w, "loadtest_rps %f\n", rps)
+
+		fmt.Fprintf(w, "# HELP loadtest_latency_seconds Request latency in seconds.\n")
+		fmt.Fprintf(w, "# TYPE loadtest_latency_seconds histogram\n")
+		for i, bound := range latencyBucketBoundsSeconds {
+			fmt.Fprintf(w, "loadtest_latency_seconds_bucket{le=\"%g\"} %d\n", bound, buckets[i])
+		}
+		fmt.Fprintf(w, "loadtest_latency_seconds_bucket{le=\"+Inf\"} %d\n", buckets[len(buckets)-1])
+		fmt.Fprintf(w, "loadtest_latency_seconds_sum %f\n", sum)
+		fmt.Fprintf(w, "loadtest_latency_seconds_count %d\n", count)
+	})
+
+	mux.HandleFunc("/live", func(w http.ResponseWriter, r *http.Request) {
+		snapshot := snapshotTestResults()
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(snapshot)
+	})
+
+	mux.HandleFunc("/snapshot", func(w http.ResponseWriter, r *http.Request) {
+		snapshot := snapshotTestResults()
+
+		if err := saveResults(snapshot, config); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, "snapshot saved")
+	})
+
+	server := &http.Server{Addr: addr, Handler: mux}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			fmt.Printf("%smetrics server error: %v%s\n", ColorYellow, err, ColorReset)
+		}
+	}()
+
+	fmt.Printf("%smetrics listening on:%s %s (/metrics, /live, /snapshot)\n", ColorBlue, ColorReset, addr)
+
+	return server
+}
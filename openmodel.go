@@ -0,0 +1,276 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jamiealquiza/tachymeter"
+)
+
+// LoadStage is one entry in `load.profile.stages`. Unlike the closed-model
+// Concurrency/Requests pair, a stage describes a target arrival rate over a
+// span of wall-clock time; the driver dispatches requests on that schedule
+// regardless of how fast the target responds.
+//
+//   - "arrival_rate" (alias "hold"): constant RatePerSec for DurationSeconds.
+//   - "ramp": linear interpolation from the previous stage's rate (or
+//     RatePerSec if this is the first stage) up to ToRatePerSec.
+//   - "spike": an instant jump to ToRatePerSec, held for DurationSeconds.
+type LoadStage struct {
+	Type            string  `yaml:"type"`
+	RatePerSec      float64 `yaml:"rate_per_sec"`
+	ToRatePerSec    float64 `yaml:"to_rate_per_sec"`
+	DurationSeconds float64 `yaml:"duration_seconds"`
+}
+
+// LoadProfile is the `load.profile` block selecting the open-model driver.
+// An empty Stages list means "not configured", leaving the existing
+// closed-model Concurrency/Requests loop in charge.
+type LoadProfile struct {
+	Stages []LoadStage `yaml:"stages"`
+}
+
+// openModelLagWarnThreshold is how far actual dispatch time may drift past
+// its intended time before a request counts as a missed deadline - evidence
+// the driver itself, not just the target, is falling behind schedule.
+const openModelLagWarnThreshold = 250 * time.Millisecond
+
+// rateAt returns the profile's target arrival rate, in requests/sec, at
+// elapsed seconds into the run. Once elapsed runs past the last stage, that
+// stage's end rate holds indefinitely.
+func (p LoadProfile) rateAt(elapsed float64) float64 {
+	var cursor, lastRate float64
+
+	for _, stage := range p.Stages {
+		if elapsed < cursor+stage.DurationSeconds {
+			within := elapsed - cursor
+			switch stage.Type {
+			case "ramp":
+				start := stage.RatePerSec
+				if start == 0 {
+					start = lastRate
+				}
+				if stage.DurationSeconds == 0 {
+					return stage.ToRatePerSec
+				}
+				frac := within / stage.DurationSeconds
+				return start + (stage.ToRatePerSec-start)*frac
+			case "spike":
+				return stage.ToRatePerSec
+			default: // "arrival_rate", "hold"
+				return stage.RatePerSec
+			}
+		}
+
+		cursor += stage.DurationSeconds
+		switch stage.Type {
+		case "ramp", "spike":
+			lastRate = stage.ToRatePerSec
+		default:
+			lastRate = stage.RatePerSec
+		}
+	}
+
+	return lastRate
+}
+
+// totalDuration sums every stage's DurationSeconds.
+func (p LoadProfile) totalDuration() float64 {
+	var total float64
+	for _, s := range p.Stages {
+		total += s.DurationSeconds
+	}
+	return total
+}
+
+// runOpenModelLoadTest drives requests against config.URL on the schedule
+// described by config.LoadProfile, rather than the closed-model
+// concurrency-limited loop in runLoadTest. Each request is dispatched in
+// its own goroutine with no semaphore - an unbounded pool - so a
+// slow target shows up as scheduling lag and growing in-flight count
+// instead of throttling the arrival rate itself (the thing a closed model
+// can't observe, known as coordinated omission).
+func runOpenModelLoadTest(config *Config) *TestResults {
+	profile := *config.LoadProfile
+	totalDuration := profile.totalDuration()
+
+	windowSize := 10000
+	t := tachymeter.New(&tachymeter.Config{Size: windowSize})
+	lagTachymeter := tachymeter.New(&tachymeter.Config{Size: windowSize})
+	histogram := newLatencyHistogram()
+	hdr := newLatencyHDR()
+
+	wallTimeStart := time.Now()
+	if config.MetricsListen != "" {
+		metricsServer := startMetricsServer(config.MetricsListen, histogram, wallTimeStart, config)
+		defer metricsServer.Close()
+	}
+
+	transport := &http.Transport{
+		MaxIdleConns:        10000,
+		IdleConnTimeout:     90 * time.Second,
+		MaxIdleConnsPerHost: 10000,
+		ForceAttemptHTTP2:   true,
+		DisableCompression:  true,
+		TLSClientConfig:     config.TLS,
+	}
+	client := &http.Client{Timeout: 30 * time.Second, Transport: transport}
+
+	protocol, err := newProtocol(protocolConfig{
+		Transport:      config.Transport,
+		URL:            config.URL,
+		REST:           config.REST,
+		GRPC:           config.GRPC,
+		Subscription:   config.Subscription,
+		RequestTimeout: config.RequestTimeout,
+	}, client)
+	if err != nil {
+		fmt.Printf("%s%v%s\n", ColorRed, err, ColorReset)
+		return &TestResults{}
+	}
+	defer protocol.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	runCancel = cancel
+
+	var logger *AsyncLogger
+	if config.LogRequests {
+		if err := os.MkdirAll(config.OutputDir, 0755); err != nil {
+			fmt.Printf("%sWarning: Failed to create output directory for logs: %v%s\n", ColorYellow, err, ColorReset)
+		} else {
+			var err error
+			logger, err = NewAsyncLogger(config.LogRequests, config.LogFile, config.LogSinks)
+			if err != nil {
+				fmt.Printf("%sWarning: Failed to create logger: %v%s\n", ColorYellow, err, ColorReset)
+			} else if err := logger.Start(); err != nil {
+				fmt.Printf("%sWarning: Failed to start logger: %v%s\n", ColorYellow, err, ColorReset)
+			} else {
+				defer logger.Stop()
+			}
+		}
+	}
+
+	var wg sync.WaitGroup
+	mu := &testResultsMu
+	successCount, failedCount := 0, 0
+	statusCodes := make(map[int]int)
+	var scheduled, dispatched, missedDeadline int64
+
+	authValue := config.BaseAuthValue
+	if authValue != "" {
+		processed := replaceRandomPlaceholders(authValue)
+		if str, ok := processed.(string); ok {
+			authValue = str
+		} else {
+			authValue = fmt.Sprintf("%v", processed)
+		}
+	}
+
+	fmt.Printf("%sopen-model load:%s %d stage(s) over %.0fs\n", ColorBlue, ColorReset, len(profile.Stages), totalDuration)
+
+	for elapsed := 0.0; elapsed < totalDuration; {
+		if atomic.LoadInt32(&gracefulShutdown) == 1 || ctx.Err() != nil {
+			break
+		}
+
+		rate := profile.rateAt(elapsed)
+		if rate <= 0 {
+			rate = 0.001 // idle stage: check back in ~1000s rather than spin
+		}
+		interval := time.Duration(float64(time.Second) / rate)
+
+		intendedAt := wallTimeStart.Add(time.Duration(elapsed * float64(time.Second)))
+		if sleep := time.Until(intendedAt); sleep > 0 {
+			select {
+			case <-time.After(sleep):
+			case <-ctx.Done():
+				elapsed = totalDuration
+				continue
+			}
+		}
+
+		atomic.AddInt64(&scheduled, 1)
+		wg.Add(1)
+
+		go func(intended time.Time, expectedInterval time.Duration) {
+			defer wg.Done()
+
+			atomic.AddInt64(&inflightRequests, 1)
+			defer atomic.AddInt64(&inflightRequests, -1)
+
+			sendTime := time.Now()
+			lag := sendTime.Sub(intended)
+			if lag > openModelLagWarnThreshold {
+				atomic.AddInt64(&missedDeadline, 1)
+			}
+			atomic.AddInt64(&dispatched, 1)
+
+			var variables map[string]interface{}
+			if config.BaseVariables != nil {
+				variables = generateRandomVariables(config.BaseVariables)
+			}
+
+			result := protocol.Do(ctx, protocolRequest{
+				Query:       config.Mutation,
+				Variables:   variables,
+				Headers:     config.Headers,
+				AuthHeader:  config.AuthHeader,
+				AuthValue:   authValue,
+				LogRequests: config.LogRequests,
+			})
+
+			if logger != nil && logger.IsEnabled() && config.Transport != "websocket" && config.Transport != "grpc" {
+				logger.LogRequest(result.StatusCode, result.RequestBody, result.ResponseBody)
+			}
+
+			t.AddTime(result.Duration)
+			histogram.observe(result.Duration)
+			hdr.observeCorrected(result.Duration, expectedInterval)
+
+			mu.Lock()
+			lagTachymeter.AddTime(lag)
+			if result.Success {
+				successCount++
+			} else {
+				failedCount++
+			}
+			statusCodes[result.StatusCode]++
+
+			testResults = &TestResults{
+				Metrics:          t.Calc(),
+				TotalRequests:    successCount + failedCount,
+				SuccessfulReqs:   successCount,
+				FailedReqs:       failedCount,
+				StatusCodes:      statusCodes,
+				WallTime:         time.Since(wallTimeStart),
+				SchedulingLag:    lagTachymeter.Calc(),
+				Scheduled:        atomic.LoadInt64(&scheduled),
+				Dispatched:       atomic.LoadInt64(&dispatched),
+				MissedDeadline:   atomic.LoadInt64(&missedDeadline),
+				LatencyHDR:       hdr,
+				LatencyCorrected: true,
+			}
+			mu.Unlock()
+		}(intendedAt, interval)
+
+		elapsed += interval.Seconds()
+	}
+
+	wg.Wait()
+
+	mu.Lock()
+	results := testResults
+	mu.Unlock()
+
+	if results == nil {
+		results = &TestResults{Metrics: t.Calc(), StatusCodes: statusCodes, SchedulingLag: lagTachymeter.Calc(), LatencyHDR: hdr, LatencyCorrected: true}
+	}
+
+	return results
+}
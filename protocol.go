@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// protocolRequest bundles the per-call inputs a Protocol needs - the things
+// that vary request to request (query, variables, headers, auth) - as
+// opposed to per-run config (REST path, gRPC target, ...), which a Protocol
+// closes over at construction time via newProtocol.
+type protocolRequest struct {
+	Query       string
+	Variables   map[string]interface{}
+	Headers     map[string]string
+	AuthHeader  string
+	AuthValue   string
+	LogRequests bool
+}
+
+// Protocol is one pluggable request transport. runLoadTest,
+// runOpenModelLoadTest, and the distributed worker all dispatch a request
+// through a Protocol instead of a transport-specific if/else chain, so
+// adding a transport means implementing this interface once rather than
+// growing a branch in every caller.
+type Protocol interface {
+	Do(ctx context.Context, req protocolRequest) RequestResult
+	Close()
+}
+
+// protocolConfig is the subset of Config a Protocol needs to build itself.
+// It exists separately from Config so distributed.go's WorkerJob (which
+// travels over the wire rather than living in-process) can populate the
+// same fields without depending on the whole Config struct.
+type protocolConfig struct {
+	Transport      string
+	URL            string
+	REST           RESTConfig
+	GRPC           GRPCConfig
+	Subscription   SubscriptionConfig
+	RequestTimeout time.Duration
+}
+
+// newProtocol resolves pc.Transport to a Protocol, mirroring the
+// newRESTRequester/newGRPCRequester constructor convention - a misconfigured
+// transport fails fast here instead of on the first request.
+func newProtocol(pc protocolConfig, client *http.Client) (Protocol, error) {
+	switch pc.Transport {
+	case "websocket":
+		return &websocketProtocol{url: pc.URL, cfg: pc.Subscription}, nil
+	case "rest":
+		requester, err := newRESTRequester(pc.REST)
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure rest transport: %w", err)
+		}
+		return &restProtocol{requester: requester, client: client, url: pc.URL, requestTimeout: pc.RequestTimeout}, nil
+	case "grpc":
+		requester, err := newGRPCRequester(pc.GRPC)
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure grpc transport: %w", err)
+		}
+		return &grpcProtocol{requester: requester, requestTimeout: pc.RequestTimeout}, nil
+	default:
+		return &graphqlProtocol{client: client, url: pc.URL, requestTimeout: pc.RequestTimeout}, nil
+	}
+}
+
+// graphqlProtocol is the default transport: a GraphQL mutation over HTTP,
+// judging success by the response's errors array (see makeRequest).
+type graphqlProtocol struct {
+	client         *http.Client
+	url            string
+	requestTimeout time.Duration
+}
+
+func (p *graphqlProtocol) Do(ctx context.Context, req protocolRequest) RequestResult {
+	payload := GraphQLRequest{Query: req.Query, Variables: req.Variables}
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return RequestResult{Error: err}
+	}
+
+	reqCtx, cancel := context.WithTimeout(ctx, p.requestTimeout)
+	defer cancel()
+	return makeRequest(reqCtx, p.client, p.url, payloadBytes, req.AuthHeader, req.AuthValue, req.Headers, req.LogRequests)
+}
+
+func (p *graphqlProtocol) Close() {}
+
+// restProtocol wraps a restRequester so it satisfies Protocol.
+type restProtocol struct {
+	requester      *restRequester
+	client         *http.Client
+	url            string
+	requestTimeout time.Duration
+}
+
+func (p *restProtocol) Do(ctx context.Context, req protocolRequest) RequestResult {
+	reqCtx, cancel := context.WithTimeout(ctx, p.requestTimeout)
+	defer cancel()
+	return p.requester.do(reqCtx, p.client, p.url, req.Variables, req.AuthHeader, req.AuthValue, req.Headers, req.LogRequests)
+}
+
+func (p *restProtocol) Close() {}
+
+// grpcProtocol wraps a grpcRequester so it satisfies Protocol, closing the
+// underlying connection when the run is done.
+type grpcProtocol struct {
+	requester      *grpcRequester
+	requestTimeout time.Duration
+}
+
+func (p *grpcProtocol) Do(ctx context.Context, req protocolRequest) RequestResult {
+	reqCtx, cancel := context.WithTimeout(ctx, p.requestTimeout)
+	defer cancel()
+	return p.requester.do(reqCtx, req.Variables)
+}
+
+func (p *grpcProtocol) Close() { p.requester.close() }
+
+// websocketProtocol adapts runSubscription's SubscriptionResult into a
+// RequestResult so callers can treat a subscription as just another Protocol
+// call instead of a special case. Messages and Disconnected ride along on
+// RequestResult for the caller to fold into SubscriptionMetrics.
+type websocketProtocol struct {
+	url string
+	cfg SubscriptionConfig
+}
+
+func (p *websocketProtocol) Do(ctx context.Context, req protocolRequest) RequestResult {
+	headers := make(map[string]string, len(req.Headers)+1)
+	for k, v := range req.Headers {
+		headers[k] = v
+	}
+	if req.AuthValue != "" {
+		headers[req.AuthHeader] = req.AuthValue
+	}
+
+	subResult := runSubscription(ctx, p.url, headers, req.Query, req.Variables, p.cfg)
+
+	statusCode := 0
+	if subResult.Success {
+		statusCode = 200
+	}
+
+	return RequestResult{
+		Duration:              subResult.FirstMessageLatency,
+		StatusCode:            statusCode,
+		Error:                 subResult.Error,
+		Success:               subResult.Success,
+		Messages:              subResult.Messages,
+		Disconnected:          subResult.Disconnected,
+		InterMessageLatencies: subResult.InterMessageLatencies,
+	}
+}
+
+func (p *websocketProtocol) Close() {}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// RESTConfig is the `rest:` block of MutationConfig, used when
+// transport: rest. Unlike the GraphQL path, the method and body are
+// templated rather than fixed, and success is judged purely on status code
+// since there's no envelope to inspect for an errors array.
+type RESTConfig struct {
+	Method       string            `yaml:"method"`
+	Path         string            `yaml:"path"`
+	BodyTemplate string            `yaml:"body_template"`
+	Headers      map[string]string `yaml:"headers"`
+	SuccessCodes []int             `yaml:"success_codes"`
+}
+
+// restRequester renders and sends one REST call per invocation. It's built
+// once in runLoadTest (parsing the body template up front) and reused by
+// every worker goroutine, mirroring how the *http.Client is built once and
+// shared.
+type restRequester struct {
+	cfg          RESTConfig
+	bodyTemplate *template.Template
+	successCodes map[int]bool
+}
+
+// newRESTRequester parses cfg.BodyTemplate once so a malformed template
+// fails fast at startup instead of on the first request.
+func newRESTRequester(cfg RESTConfig) (*restRequester, error) {
+	r := &restRequester{cfg: cfg}
+
+	if cfg.BodyTemplate != "" {
+		tmpl, err := template.New("rest-body").Parse(cfg.BodyTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse rest.body_template: %w", err)
+		}
+		r.bodyTemplate = tmpl
+	}
+
+	if len(cfg.SuccessCodes) > 0 {
+		r.successCodes = make(map[int]bool, len(cfg.SuccessCodes))
+		for _, code := range cfg.SuccessCodes {
+			r.successCodes[code] = true
+		}
+	}
+
+	return r, nil
+}
+
+// do renders the configured body template against variables and sends the
+// request, judging success by cfg.SuccessCodes (default: any 2xx) instead of
+// the GraphQL errors-array convention makeRequest uses.
+func (r *restRequester) do(ctx context.Context, client *http.Client, baseURL string, variables map[string]interface{}, authHeader, authValue string, headers map[string]string, logRequests bool) RequestResult {
+	start := time.Now()
+
+	var bodyBytes []byte
+	if r.bodyTemplate != nil {
+		var buf bytes.Buffer
+		if err := r.bodyTemplate.Execute(&buf, variables); err != nil {
+			return RequestResult{Duration: time.Since(start), Error: err, Success: false}
+		}
+		bodyBytes = buf.Bytes()
+	}
+
+	method := r.cfg.Method
+	if method == "" {
+		method = "GET"
+	}
+
+	url := baseURL
+	if r.cfg.Path != "" {
+		url = strings.TrimRight(baseURL, "/") + "/" + strings.TrimLeft(r.cfg.Path, "/")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return RequestResult{Duration: time.Since(start), Error: err, Success: false}
+	}
+
+	if len(bodyBytes) > 0 {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if authValue != "" {
+		req.Header.Set(authHeader, authValue)
+	}
+	for k, v := range r.cfg.Headers {
+		req.Header.Set(k, v)
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return RequestResult{Duration: time.Since(start), Error: err, Success: false}
+	}
+	defer resp.Body.Close()
+
+	duration := time.Since(start)
+	respBody, _ := io.ReadAll(resp.Body)
+
+	success := r.successCodes != nil && r.successCodes[resp.StatusCode]
+	if r.successCodes == nil {
+		success = resp.StatusCode >= 200 && resp.StatusCode < 300
+	}
+
+	result := RequestResult{
+		Duration:   duration,
+		StatusCode: resp.StatusCode,
+		Success:    success,
+	}
+
+	if logRequests {
+		result.RequestBody = string(bodyBytes)
+		result.ResponseBody = string(respBody)
+	}
+
+	return result
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"sync"
+
+	"github.com/jamiealquiza/tachymeter"
+)
+
+// Scenario is one entry in the `scenarios:` list. When scenarios are
+// present, each worker iteration picks one (weighted by Weight) instead of
+// always running the single top-level query/variables pair.
+type Scenario struct {
+	Name      string                 `yaml:"name"`
+	Query     string                 `yaml:"query"`
+	Variables map[string]interface{} `yaml:"variables"`
+	Headers   map[string]string      `yaml:"headers"`
+	Weight    int                    `yaml:"weight"`
+}
+
+// ScenarioStats accumulates results for a single scenario across the run,
+// mirroring the aggregate counters in runLoadTest but scoped to one
+// scenario's own tachymeter window.
+type ScenarioStats struct {
+	Name         string
+	Tachymeter   *tachymeter.Tachymeter
+	mu           sync.Mutex
+	SuccessCount int
+	FailedCount  int
+	StatusCodes  map[int]int
+}
+
+func newScenarioStats(name string, windowSize int) *ScenarioStats {
+	return &ScenarioStats{
+		Name:        name,
+		Tachymeter:  tachymeter.New(&tachymeter.Config{Size: windowSize}),
+		StatusCodes: make(map[int]int),
+	}
+}
+
+// record folds one request's outcome into this scenario's stats. Safe for
+// concurrent use by worker goroutines.
+func (s *ScenarioStats) record(result RequestResult) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.Tachymeter.AddTime(result.Duration)
+	if result.Success {
+		s.SuccessCount++
+	} else {
+		s.FailedCount++
+	}
+	s.StatusCodes[result.StatusCode]++
+}
+
+// aliasSampler is a Vose alias-method sampler: O(n) to build, O(1) to draw
+// from, so picking a weighted scenario per request doesn't cost more than
+// the uniform case even with many scenarios.
+type aliasSampler struct {
+	prob  []float64
+	alias []int
+}
+
+func newAliasSampler(weights []int) *aliasSampler {
+	n := len(weights)
+	total := 0
+	for _, w := range weights {
+		total += w
+	}
+
+	scaled := make([]float64, n)
+	for i, w := range weights {
+		scaled[i] = float64(w) * float64(n) / float64(total)
+	}
+
+	small := make([]int, 0, n)
+	large := make([]int, 0, n)
+	for i, p := range scaled {
+		if p < 1.0 {
+			small = append(small, i)
+		} else {
+			large = append(large, i)
+		}
+	}
+
+	prob := make([]float64, n)
+	alias := make([]int, n)
+
+	for len(small) > 0 && len(large) > 0 {
+		s := small[len(small)-1]
+		small = small[:len(small)-1]
+		l := large[len(large)-1]
+		large = large[:len(large)-1]
+
+		prob[s] = scaled[s]
+		alias[s] = l
+
+		scaled[l] = scaled[l] + scaled[s] - 1.0
+		if scaled[l] < 1.0 {
+			small = append(small, l)
+		} else {
+			large = append(large, l)
+		}
+	}
+
+	for len(large) > 0 {
+		l := large[len(large)-1]
+		large = large[:len(large)-1]
+		prob[l] = 1.0
+	}
+	for len(small) > 0 {
+		s := small[len(small)-1]
+		small = small[:len(small)-1]
+		prob[s] = 1.0
+	}
+
+	return &aliasSampler{prob: prob, alias: alias}
+}
+
+// sample draws a weighted-random index in O(1) using the given uniform
+// variates u (column choice) and v (coin flip), so callers can supply their
+// own source of randomness.
+func (a *aliasSampler) sample(u float64, v float64) int {
+	n := len(a.prob)
+	col := int(u * float64(n))
+	if col >= n {
+		col = n - 1
+	}
+	if v < a.prob[col] {
+		return col
+	}
+	return a.alias[col]
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+)
+
+// SinkConfig is one entry in the `logging.sinks:` list. Type selects the
+// implementation; the remaining fields are interpreted by that
+// implementation only (File for csv/jsonl, Endpoint/Headers for otlp).
+type SinkConfig struct {
+	Type     string            `yaml:"type"`
+	File     string            `yaml:"file"`
+	Endpoint string            `yaml:"endpoint"`
+	Headers  map[string]string `yaml:"headers"`
+}
+
+// Sink receives one RequestLogEntry at a time from AsyncLogger's worker
+// goroutine. Implementations should be safe to call sequentially from that
+// single goroutine only - AsyncLogger never calls a Sink concurrently with
+// itself.
+type Sink interface {
+	Write(entry RequestLogEntry) error
+	Close() error
+}
+
+// newSink builds the Sink selected by cfg.Type, defaulting to "csv" to
+// match AsyncLogger's pre-existing behavior.
+func newSink(cfg SinkConfig) (Sink, error) {
+	switch cfg.Type {
+	case "", "csv":
+		return newCSVSink(cfg.File)
+	case "jsonl":
+		return newJSONLSink(cfg.File)
+	case "otlp":
+		return newOTLPSink(cfg)
+	default:
+		return nil, fmt.Errorf("unknown logging sink type %q", cfg.Type)
+	}
+}
+
+// csvSink is AsyncLogger's original behavior, extracted behind the Sink
+// interface unchanged: one row per request, flushed after every write.
+type csvSink struct {
+	file   *os.File
+	writer *csv.Writer
+}
+
+func newCSVSink(path string) (*csvSink, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create csv sink file %q: %w", path, err)
+	}
+
+	writer := csv.NewWriter(file)
+	if err := writer.Write([]string{"Date", "Status", "Request", "Response"}); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to write csv header: %w", err)
+	}
+
+	return &csvSink{file: file, writer: writer}, nil
+}
+
+func (s *csvSink) Write(entry RequestLogEntry) error {
+	if err := s.writer.Write([]string{entry.Date, strconv.Itoa(entry.Status), entry.Request, entry.Response}); err != nil {
+		return err
+	}
+	s.writer.Flush()
+	return s.writer.Error()
+}
+
+func (s *csvSink) Close() error {
+	s.writer.Flush()
+	return s.file.Close()
+}
+
+// jsonlSink writes one JSON-encoded RequestLogEntry per line, for tools
+// that would rather stream-parse JSON than CSV.
+type jsonlSink struct {
+	file *os.File
+}
+
+func newJSONLSink(path string) (*jsonlSink, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create jsonl sink file %q: %w", path, err)
+	}
+	return &jsonlSink{file: file}, nil
+}
+
+func (s *jsonlSink) Write(entry RequestLogEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	_, err = s.file.Write(append(line, '\n'))
+	return err
+}
+
+func (s *jsonlSink) Close() error {
+	return s.file.Close()
+}
+
+// otlpSink ships each request as a zero-duration OTLP/HTTP span to a
+// collector's /v1/traces endpoint. RequestLogEntry only records a
+// completion timestamp, not a start time, so the span's start and end are
+// both set to that timestamp - enough to see the request in a trace
+// backend, not to measure its duration there.
+type otlpSink struct {
+	endpoint string
+	headers  map[string]string
+	client   *http.Client
+}
+
+func newOTLPSink(cfg SinkConfig) (*otlpSink, error) {
+	if cfg.Endpoint == "" {
+		return nil, fmt.Errorf("logging sink type \"otlp\" requires an endpoint")
+	}
+	return &otlpSink{
+		endpoint: cfg.Endpoint,
+		headers:  cfg.Headers,
+		client:   &http.Client{Timeout: 5 * time.Second},
+	}, nil
+}
+
+func (s *otlpSink) Write(entry RequestLogEntry) error {
+	body, err := json.Marshal(otlpExportFromEntry(entry))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range s.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("otlp collector returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (s *otlpSink) Close() error {
+	return nil
+}
+
+// OTLP/HTTP trace export JSON shape (the subset this sink produces).
+type otlpTraceExport struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpScopeSpans struct {
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	Name              string         `json:"name"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes"`
+	Status            otlpStatus     `json:"status"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue string `json:"stringValue,omitempty"`
+	IntValue    string `json:"intValue,omitempty"`
+}
+
+// otlpStatus.Code follows the OTLP StatusCode enum: 0 unset, 1 ok, 2 error.
+type otlpStatus struct {
+	Code int `json:"code"`
+}
+
+func otlpExportFromEntry(entry RequestLogEntry) otlpTraceExport {
+	ts := time.Now()
+	if parsed, err := time.Parse("2006-01-02 15:04:05", entry.Date); err == nil {
+		ts = parsed
+	}
+	nano := strconv.FormatInt(ts.UnixNano(), 10)
+
+	statusCode := 1
+	if entry.Status == 0 || entry.Status >= 400 {
+		statusCode = 2
+	}
+
+	return otlpTraceExport{
+		ResourceSpans: []otlpResourceSpans{{
+			ScopeSpans: []otlpScopeSpans{{
+				Spans: []otlpSpan{{
+					TraceID:           randomHex(16),
+					SpanID:            randomHex(8),
+					Name:              "load-tester.request",
+					StartTimeUnixNano: nano,
+					EndTimeUnixNano:   nano,
+					Attributes: []otlpKeyValue{
+						{Key: "http.status_code", Value: otlpAnyValue{IntValue: strconv.Itoa(entry.Status)}},
+						{Key: "request.body", Value: otlpAnyValue{StringValue: entry.Request}},
+						{Key: "response.body", Value: otlpAnyValue{StringValue: entry.Response}},
+					},
+					Status: otlpStatus{Code: statusCode},
+				}},
+			}},
+		}},
+	}
+}
+
+// randomHex returns n random bytes hex-encoded, for OTLP trace/span IDs.
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/jamiealquiza/tachymeter"
+)
+
+// SubscriptionMetrics aggregates per-connection SubscriptionResults across
+// the whole run, alongside the shared tachymeter window used for overall
+// latency. FirstMessage tracks only the dial-to-first-`next` latency so a
+// slow handshake doesn't get conflated with steady-state message delivery;
+// InterMessage tracks the gap between subsequent `next` frames, i.e.
+// steady-state delivery latency once a connection is established.
+type SubscriptionMetrics struct {
+	TotalMessages int
+	Disconnects   int
+	FirstMessage  *tachymeter.Tachymeter
+	InterMessage  *tachymeter.Tachymeter
+}
+
+// SubscriptionConfig configures a graphql-transport-ws (or legacy graphql-ws)
+// subscription run. It is the `subscription:` block of MutationConfig.
+type SubscriptionConfig struct {
+	InitPayload       map[string]interface{} `yaml:"init_payload"`
+	ConnectionParams  map[string]interface{} `yaml:"connection_params"`
+	SubscribeTimeout  int                    `yaml:"subscribe_timeout_seconds"`
+	MessageBudget     int                    `yaml:"message_budget"`
+	DurationSeconds   int                    `yaml:"duration_seconds"`
+	MaxFrameSizeBytes int64                  `yaml:"max_frame_size_bytes"`
+}
+
+// graphqlWSMessage is the envelope used by both the graphql-transport-ws and
+// the legacy graphql-ws subprotocols.
+type graphqlWSMessage struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// SubscriptionResult captures the per-connection outcome of a single
+// subscription "request" so it can be folded into the same success/failure
+// and latency accounting as an HTTP makeRequest call.
+type SubscriptionResult struct {
+	Success               bool
+	Error                 error
+	Messages              int
+	FirstMessageLatency   time.Duration
+	InterMessageLatencies []time.Duration
+	Disconnected          bool
+}
+
+const (
+	defaultMaxFrameSizeBytes   = 1 << 20 // 1 MiB, well above gorilla's 64 KB default read buffer
+	defaultSubscribeTimeoutSec = 10
+)
+
+// runSubscription dials a graphql-transport-ws endpoint, completes the
+// connection_init/connection_ack handshake, issues one subscribe, and then
+// counts `next` frames as responses until the message budget or duration is
+// exhausted, the server sends `complete`, or ctx is cancelled.
+func runSubscription(ctx context.Context, wsURL string, headers map[string]string, query string, variables map[string]interface{}, cfg SubscriptionConfig) SubscriptionResult {
+	start := time.Now()
+
+	maxFrame := cfg.MaxFrameSizeBytes
+	if maxFrame <= 0 {
+		maxFrame = defaultMaxFrameSizeBytes
+	}
+
+	subscribeTimeout := time.Duration(cfg.SubscribeTimeout) * time.Second
+	if subscribeTimeout <= 0 {
+		subscribeTimeout = defaultSubscribeTimeoutSec * time.Second
+	}
+
+	reqHeader := http.Header{}
+	for k, v := range headers {
+		reqHeader.Set(k, v)
+	}
+
+	dialer := websocket.Dialer{
+		Subprotocols:     []string{"graphql-transport-ws", "graphql-ws"},
+		HandshakeTimeout: subscribeTimeout,
+	}
+
+	conn, _, err := dialer.DialContext(ctx, wsURL, reqHeader)
+	if err != nil {
+		return SubscriptionResult{Success: false, Error: fmt.Errorf("dial: %w", err)}
+	}
+	defer conn.Close()
+
+	conn.SetReadLimit(maxFrame)
+
+	initPayload, err := json.Marshal(cfg.InitPayload)
+	if err != nil {
+		return SubscriptionResult{Success: false, Error: fmt.Errorf("marshal init payload: %w", err)}
+	}
+	if err := conn.WriteJSON(graphqlWSMessage{Type: "connection_init", Payload: initPayload}); err != nil {
+		return SubscriptionResult{Success: false, Error: fmt.Errorf("connection_init: %w", err)}
+	}
+
+	conn.SetReadDeadline(time.Now().Add(subscribeTimeout))
+	var ack graphqlWSMessage
+	if err := conn.ReadJSON(&ack); err != nil {
+		return SubscriptionResult{Success: false, Error: fmt.Errorf("waiting for connection_ack: %w", err)}
+	}
+	if ack.Type != "connection_ack" {
+		return SubscriptionResult{Success: false, Error: fmt.Errorf("expected connection_ack, got %q", ack.Type)}
+	}
+
+	subPayload, err := json.Marshal(struct {
+		Query     string                 `json:"query"`
+		Variables map[string]interface{} `json:"variables,omitempty"`
+	}{Query: query, Variables: variables})
+	if err != nil {
+		return SubscriptionResult{Success: false, Error: fmt.Errorf("marshal subscribe payload: %w", err)}
+	}
+
+	const subscriptionID = "1"
+	if err := conn.WriteJSON(graphqlWSMessage{ID: subscriptionID, Type: "subscribe", Payload: subPayload}); err != nil {
+		return SubscriptionResult{Success: false, Error: fmt.Errorf("subscribe: %w", err)}
+	}
+	conn.SetReadDeadline(time.Now().Add(subscribeTimeout))
+
+	result := SubscriptionResult{Success: true}
+
+	messageBudget := cfg.MessageBudget
+	var deadline <-chan time.Time
+	if cfg.DurationSeconds > 0 {
+		timer := time.NewTimer(time.Duration(cfg.DurationSeconds) * time.Second)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+
+	lastMessage := start
+	for {
+		if messageBudget > 0 && result.Messages >= messageBudget {
+			break
+		}
+
+		// Reset per message rather than once after subscribe, so
+		// subscribeTimeout bounds the gap between frames (a liveness check)
+		// instead of silently becoming an absolute cutoff for the whole
+		// subscription regardless of cfg.DurationSeconds/MessageBudget.
+		conn.SetReadDeadline(time.Now().Add(subscribeTimeout))
+
+		msgCh := make(chan graphqlWSMessage, 1)
+		errCh := make(chan error, 1)
+		go func() {
+			var msg graphqlWSMessage
+			if err := conn.ReadJSON(&msg); err != nil {
+				errCh <- err
+				return
+			}
+			msgCh <- msg
+		}()
+
+		select {
+		case <-ctx.Done():
+			result.Disconnected = true
+			conn.WriteJSON(graphqlWSMessage{ID: subscriptionID, Type: "complete"})
+			return result
+		case <-deadline:
+			conn.WriteJSON(graphqlWSMessage{ID: subscriptionID, Type: "complete"})
+			return result
+		case err := <-errCh:
+			if websocket.IsUnexpectedCloseError(err) {
+				result.Disconnected = true
+			}
+			if result.Messages == 0 {
+				result.Success = false
+				result.Error = fmt.Errorf("reading subscription frame: %w", err)
+			}
+			return result
+		case msg := <-msgCh:
+			switch msg.Type {
+			case "next":
+				now := time.Now()
+				if result.Messages == 0 {
+					result.FirstMessageLatency = now.Sub(start)
+				} else {
+					result.InterMessageLatencies = append(result.InterMessageLatencies, now.Sub(lastMessage))
+				}
+				lastMessage = now
+				result.Messages++
+			case "error":
+				result.Success = false
+				result.Error = fmt.Errorf("subscription error: %s", strings.TrimSpace(string(msg.Payload)))
+				return result
+			case "complete":
+				return result
+			}
+		}
+	}
+
+	conn.WriteJSON(graphqlWSMessage{ID: subscriptionID, Type: "complete"})
+	return result
+}
@@ -2,8 +2,8 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"crypto/rand"
-	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -215,17 +215,22 @@ type RequestLogEntry struct {
 	Response string
 }
 
+// AsyncLogger fans each logged request out to one or more Sinks from a
+// single background goroutine, so a slow sink (e.g. an OTLP collector over
+// the network) can't add latency to the request-handling path.
 type AsyncLogger struct {
 	enabled    bool
-	logFile    *os.File
-	csvWriter  *csv.Writer
+	sinks      []Sink
 	logChannel chan RequestLogEntry
 	waitGroup  sync.WaitGroup
 	started    bool
 }
 
-// NewAsyncLogger creates a new async logger instance
-func NewAsyncLogger(enabled bool, logFilePath string) (*AsyncLogger, error) {
+// NewAsyncLogger creates a new async logger instance. sinkConfigs is the
+// logging.sinks: list; if empty and logFilePath is set, it falls back to a
+// single CSV sink at logFilePath so existing logging.file configs keep
+// working unchanged.
+func NewAsyncLogger(enabled bool, logFilePath string, sinkConfigs []SinkConfig) (*AsyncLogger, error) {
 	logger := &AsyncLogger{
 		enabled: enabled,
 	}
@@ -234,14 +239,18 @@ func NewAsyncLogger(enabled bool, logFilePath string) (*AsyncLogger, error) {
 		return logger, nil
 	}
 
-	// Create the log file
-	logFile, err := os.Create(logFilePath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create log file: %w", err)
+	if len(sinkConfigs) == 0 && logFilePath != "" {
+		sinkConfigs = []SinkConfig{{Type: "csv", File: logFilePath}}
+	}
+
+	for _, sc := range sinkConfigs {
+		sink, err := newSink(sc)
+		if err != nil {
+			return nil, err
+		}
+		logger.sinks = append(logger.sinks, sink)
 	}
 
-	logger.logFile = logFile
-	logger.csvWriter = csv.NewWriter(logFile)
 	logger.logChannel = make(chan RequestLogEntry, 1000) // Buffer 1000 entries
 
 	return logger, nil
@@ -253,11 +262,6 @@ func (al *AsyncLogger) Start() error {
 		return nil
 	}
 
-	// Write CSV header
-	if err := al.csvWriter.Write([]string{"Date", "Status", "Request", "Response"}); err != nil {
-		return fmt.Errorf("failed to write CSV header: %w", err)
-	}
-
 	// Start the async logging goroutine
 	al.waitGroup.Add(1)
 	go al.logWorker()
@@ -266,21 +270,17 @@ func (al *AsyncLogger) Start() error {
 	return nil
 }
 
-// logWorker processes log entries asynchronously
+// logWorker processes log entries asynchronously, writing each one to
+// every configured sink.
 func (al *AsyncLogger) logWorker() {
 	defer al.waitGroup.Done()
-	defer al.csvWriter.Flush()
 
 	for entry := range al.logChannel {
-		al.csvWriter.Write([]string{
-			entry.Date,
-			fmt.Sprintf("%d", entry.Status),
-			entry.Request,
-			entry.Response,
-		})
-
-		// Flush periodically to avoid losing data
-		al.csvWriter.Flush()
+		for _, sink := range al.sinks {
+			if err := sink.Write(entry); err != nil {
+				fmt.Printf("%sWarning: log sink write failed: %v%s\n", ColorYellow, err, ColorReset)
+			}
+		}
 	}
 }
 
@@ -326,15 +326,15 @@ func (al *AsyncLogger) Stop() error {
 	close(al.logChannel)
 	al.waitGroup.Wait()
 
-	// Close the file
-	if al.logFile != nil {
-		if err := al.logFile.Close(); err != nil {
-			return fmt.Errorf("failed to close log file: %w", err)
+	var firstErr error
+	for _, sink := range al.sinks {
+		if err := sink.Close(); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("failed to close log sink: %w", err)
 		}
 	}
 
 	al.started = false
-	return nil
+	return firstErr
 }
 
 // IsEnabled returns whether logging is enabled
@@ -342,8 +342,6 @@ func (al *AsyncLogger) IsEnabled() bool {
 	return al.enabled
 }
 
-
-
 func loadConfigFromFile(filename, environment string) (*Config, error) {
 	data, err := os.ReadFile(filename)
 	if err != nil {
@@ -371,6 +369,9 @@ func loadConfigFromFile(filename, environment string) (*Config, error) {
 	if yamlConfig.Load.Requests == 0 {
 		yamlConfig.Load.Requests = 100
 	}
+	if yamlConfig.Load.RequestTimeoutSec == 0 {
+		yamlConfig.Load.RequestTimeoutSec = 30
+	}
 
 	// Validate required fields
 	if envConfig.URL == "" {
@@ -383,6 +384,11 @@ func loadConfigFromFile(filename, environment string) (*Config, error) {
 		return nil, fmt.Errorf("auth value is required for environment '%s' in config file", environment)
 	}
 
+	tlsConfig, err := buildTLSConfig(envConfig.TLS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure TLS for environment '%s': %w", environment, err)
+	}
+
 	// Set default log file if logging is enabled but no file specified
 	logFile := yamlConfig.Logging.LogFile
 	if yamlConfig.Logging.Enabled && logFile == "" {
@@ -391,19 +397,38 @@ func loadConfigFromFile(filename, environment string) (*Config, error) {
 	}
 
 	config := &Config{
-		URL:           envConfig.URL,
-		Mutation:      yamlConfig.Query,
-		AuthHeader:    envConfig.Auth.Header,
-		AuthValue:     envConfig.Auth.Value,
-		BaseAuthValue: envConfig.Auth.Value,
-		Concurrency:   yamlConfig.Load.Concurrency,
-		TotalReqs:     yamlConfig.Load.Requests,
-		BaseVariables: yamlConfig.Variables,
-		ShowProgress:  true,
-		SaveResults:   true,
-		OutputDir:     fmt.Sprintf("results/%s", environment),
-		LogRequests:   yamlConfig.Logging.Enabled,
-		LogFile:       logFile,
+		URL:            envConfig.URL,
+		Mutation:       yamlConfig.Query,
+		AuthHeader:     envConfig.Auth.Header,
+		AuthValue:      envConfig.Auth.Value,
+		BaseAuthValue:  envConfig.Auth.Value,
+		Headers:        yamlConfig.Headers,
+		Concurrency:    yamlConfig.Load.Concurrency,
+		TotalReqs:      yamlConfig.Load.Requests,
+		TargetRPS:      yamlConfig.Load.RPS,
+		DurationSec:    yamlConfig.Load.DurationSec,
+		RequestTimeout: time.Duration(yamlConfig.Load.RequestTimeoutSec) * time.Second,
+		BaseVariables:  yamlConfig.Variables,
+		ShowProgress:   true,
+		SaveResults:    true,
+		OutputDir:      fmt.Sprintf("results/%s", environment),
+		LogRequests:    yamlConfig.Logging.Enabled,
+		LogFile:        logFile,
+		LogSinks:       yamlConfig.Logging.Sinks,
+		Transport:      yamlConfig.Transport,
+		Subscription:   yamlConfig.Subscription,
+		Warmup:         yamlConfig.Warmup,
+		Scenarios:      yamlConfig.Scenarios,
+		Journeys:       yamlConfig.Journeys,
+		TLS:            tlsConfig,
+		MetricsListen:  yamlConfig.Metrics.Listen,
+		REST:           yamlConfig.REST,
+		GRPC:           yamlConfig.GRPC,
+	}
+
+	if len(yamlConfig.Load.Profile.Stages) > 0 {
+		profile := yamlConfig.Load.Profile
+		config.LoadProfile = &profile
 	}
 
 	return config, nil
@@ -415,17 +440,24 @@ func setupSignalHandling() {
 
 	go func() {
 		<-c
-		fmt.Printf("\n%s%sReceived interrupt signal, finishing current requests...%s\n", ColorBold, ColorYellow, ColorReset)
+		fmt.Printf("\n%s%sReceived interrupt signal, tearing down in-flight requests...%s\n", ColorBold, ColorYellow, ColorReset)
 		atomic.StoreInt32(&gracefulShutdown, 1)
+		if runCancel != nil {
+			runCancel()
+		}
 
 		time.Sleep(2 * time.Second)
 
-		if testResults != nil {
+		testResultsMu.Lock()
+		snapshot := testResults
+		testResultsMu.Unlock()
+
+		if snapshot != nil {
 			fmt.Printf("%s%sTest interrupted - showing partial results:%s\n", ColorBold, ColorYellow, ColorReset)
-			printResults(testResults)
+			printResults(snapshot)
 
 			if testConfig != nil && testConfig.SaveResults {
-				if err := saveResults(testResults, testConfig); err != nil {
+				if err := saveResults(snapshot, testConfig); err != nil {
 					fmt.Printf("%sError saving results: %v%s\n", ColorRed, err, ColorReset)
 				}
 			}
@@ -435,11 +467,14 @@ func setupSignalHandling() {
 	}()
 }
 
-// makeRequest performs a single HTTP request and returns the result
-func makeRequest(client *http.Client, url string, payload []byte, authHeader, authValue string, logRequests bool) RequestResult {
+// makeRequest performs a single HTTP request bound to ctx's deadline and
+// returns the result. Callers are expected to derive ctx from the run-level
+// context with their own per-request timeout, so a cancelled run tears down
+// in-flight requests immediately instead of waiting out client.Timeout.
+func makeRequest(ctx context.Context, client *http.Client, url string, payload []byte, authHeader, authValue string, headers map[string]string, logRequests bool) RequestResult {
 	start := time.Now()
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(payload))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(payload))
 	if err != nil {
 		return RequestResult{
 			Duration: time.Since(start),
@@ -450,6 +485,9 @@ func makeRequest(client *http.Client, url string, payload []byte, authHeader, au
 
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set(authHeader, authValue)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
 
 	resp, err := client.Do(req)
 	if err != nil {
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/jamiealquiza/tachymeter"
+)
+
+// WarmupConfig is the `warmup:` block of MutationConfig. When enabled, it
+// replaces the hard-coded p95 guess used to derive concurrency from
+// TargetRPS with an observed one, taken from a small burst run before the
+// real test starts. That burst's requests are always thrown away once
+// concurrency is derived unless KeepResults is explicitly set, in which case
+// they're folded into the real run's metrics and status-code counts instead
+// - e.g. when the warm-up burst is itself traffic worth counting rather than
+// pure calibration. KeepResults defaults to false (discard) rather than the
+// reverse, so an absent `keep_results` key can't be mistaken for an explicit
+// opt-in to contaminating the reported metrics.
+type WarmupConfig struct {
+	Enabled         bool `yaml:"enabled"`
+	Requests        int  `yaml:"requests"`
+	Concurrency     int  `yaml:"concurrency"`
+	DurationSeconds int  `yaml:"duration_seconds"`
+	MinConcurrency  int  `yaml:"min_concurrency"`
+	MaxConcurrency  int  `yaml:"max_concurrency"`
+	KeepResults     bool `yaml:"keep_results"`
+}
+
+const (
+	warmupP95Floor   = 5 * time.Millisecond
+	warmupP95Ceiling = 5 * time.Second
+)
+
+// runWarmup fires a small burst of requests against config.URL, observes
+// their p95 latency, and applies Little's law (concurrency = ceil(rps *
+// p95_seconds)) to size the semaphore for the real run. Its own results are
+// kept in a throwaway tachymeter window, separate from the caller's metrics
+// and status-code map, unless cfg.KeepResults is set, in which case the
+// individual RequestResults are also returned so the caller can fold them
+// in instead.
+func runWarmup(config *Config) (int, []RequestResult) {
+	cfg := config.Warmup
+
+	requests := cfg.Requests
+	if requests <= 0 {
+		requests = config.TargetRPS
+		if requests < 20 {
+			requests = 20
+		}
+	}
+
+	concurrency := cfg.Concurrency
+	if concurrency <= 0 {
+		concurrency = config.TargetRPS / 10
+		if concurrency < 5 {
+			concurrency = 5
+		}
+	}
+
+	transport := &http.Transport{
+		MaxIdleConns:        concurrency * 2,
+		IdleConnTimeout:     90 * time.Second,
+		MaxIdleConnsPerHost: concurrency * 2,
+		MaxConnsPerHost:     concurrency * 2,
+		ForceAttemptHTTP2:   true,
+		DisableCompression:  true,
+		TLSClientConfig:     config.TLS,
+	}
+	client := &http.Client{
+		Timeout:   30 * time.Second,
+		Transport: transport,
+	}
+
+	warmupTachymeter := tachymeter.New(&tachymeter.Config{Size: requests})
+
+	var wg sync.WaitGroup
+	var resultsMu sync.Mutex
+	var collected []RequestResult
+	if cfg.KeepResults {
+		collected = make([]RequestResult, 0, requests)
+	}
+	semaphore := make(chan struct{}, concurrency)
+
+	deadline := time.Time{}
+	if cfg.DurationSeconds > 0 {
+		deadline = time.Now().Add(time.Duration(cfg.DurationSeconds) * time.Second)
+	}
+
+	for i := 0; i < requests; i++ {
+		if !deadline.IsZero() && time.Now().After(deadline) {
+			break
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			var variables map[string]interface{}
+			if config.BaseVariables != nil {
+				variables = generateRandomVariables(config.BaseVariables)
+			}
+
+			payload := GraphQLRequest{Query: config.Mutation, Variables: variables}
+			payloadBytes, err := json.Marshal(payload)
+			if err != nil {
+				return
+			}
+
+			reqCtx, reqCancel := context.WithTimeout(context.Background(), config.RequestTimeout)
+			result := makeRequest(reqCtx, client, config.URL, payloadBytes, config.AuthHeader, config.BaseAuthValue, config.Headers, false)
+			reqCancel()
+			warmupTachymeter.AddTime(result.Duration)
+
+			if cfg.KeepResults {
+				resultsMu.Lock()
+				collected = append(collected, result)
+				resultsMu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	observedP95 := warmupTachymeter.Calc().Time.P95
+	if observedP95 < warmupP95Floor {
+		observedP95 = warmupP95Floor
+	}
+	if observedP95 > warmupP95Ceiling {
+		observedP95 = warmupP95Ceiling
+	}
+
+	derived := int(math.Ceil(float64(config.TargetRPS) * observedP95.Seconds()))
+	if cfg.MinConcurrency > 0 && derived < cfg.MinConcurrency {
+		derived = cfg.MinConcurrency
+	}
+	if cfg.MaxConcurrency > 0 && derived > cfg.MaxConcurrency {
+		derived = cfg.MaxConcurrency
+	}
+	if derived < 1 {
+		derived = 1
+	}
+
+	fmt.Printf("%swarm-up:%s sampled p95 %s%s%s over %d requests -> derived concurrency %s%d%s\n",
+		ColorBlue, ColorReset, ColorBold, observedP95, ColorReset, requests, ColorBold, derived, ColorReset)
+
+	return derived, collected
+}